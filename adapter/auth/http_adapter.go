@@ -0,0 +1,54 @@
+package adapter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/adapter/httpx"
+	port "github.com/wittawat/go-hex/core/port/auth"
+)
+
+type HttpAuthHandler struct {
+	ib port.AuthInbound
+}
+
+func NewHttpAuthHandler(ib port.AuthInbound) *HttpAuthHandler {
+	return &HttpAuthHandler{ib: ib}
+}
+
+type loginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func (h *HttpAuthHandler) Login(c *gin.Context) {
+	var req loginRequest
+	if !httpx.BindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := h.ib.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Logged in successfully", tokens)
+}
+
+func (h *HttpAuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if !httpx.BindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := h.ib.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Refreshed token successfully", tokens)
+}
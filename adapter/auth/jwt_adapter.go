@@ -0,0 +1,77 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type jwtClaims struct {
+	Email string             `json:"email"`
+	Role  entities.Role      `json:"role"`
+	Kind  entities.TokenKind `json:"token_use"`
+	jwt.RegisteredClaims
+}
+
+// JwtTokenProvider signs HS256 tokens with a shared secret.
+type JwtTokenProvider struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewJwtTokenProvider(secret []byte, issuer string, accessTTL, refreshTTL time.Duration) *JwtTokenProvider {
+	return &JwtTokenProvider{secret: secret, issuer: issuer, accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+func (p *JwtTokenProvider) Issue(_ context.Context, identity entities.Identity, kind entities.TokenKind) (string, time.Time, error) {
+	ttl := p.accessTTL
+	if kind == entities.RefreshToken {
+		ttl = p.refreshTTL
+	}
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims := jwtClaims{
+		Email: identity.Email,
+		Role:  identity.Role,
+		Kind:  kind,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.issuer,
+			Subject:   strconv.Itoa(identity.UserId),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(p.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+func (p *JwtTokenProvider) Parse(_ context.Context, token string, kind entities.TokenKind) (*entities.Identity, error) {
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return p.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(p.issuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Kind != kind {
+		return nil, fmt.Errorf("token is a %s token, want %s", claims.Kind, kind)
+	}
+
+	userId, err := strconv.Atoi(claims.Subject)
+	if err != nil || userId <= 0 {
+		return nil, errors.New("token has an invalid subject")
+	}
+	return &entities.Identity{UserId: userId, Email: claims.Email, Role: claims.Role}, nil
+}
@@ -0,0 +1,214 @@
+package adapter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+var (
+	testSecret   = []byte("0123456789abcdef0123456789abcdef")
+	testIdentity = entities.Identity{UserId: 7, Email: "alice@example.com", Role: entities.RoleStaff}
+)
+
+func TestJwtTokenProviderRoundTrip(t *testing.T) {
+	provider := NewJwtTokenProvider(testSecret, "go-hex", 15*time.Minute, 24*time.Hour)
+
+	for _, tt := range []struct {
+		kind entities.TokenKind
+		ttl  time.Duration
+	}{
+		{kind: entities.AccessToken, ttl: 15 * time.Minute},
+		{kind: entities.RefreshToken, ttl: 24 * time.Hour},
+	} {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			before := time.Now()
+			token, expiresAt, err := provider.Issue(t.Context(), testIdentity, tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// NumericDate keeps whole seconds
+			if want := before.Add(tt.ttl); expiresAt.Before(want.Add(-time.Second)) || expiresAt.After(want.Add(time.Second)) {
+				t.Errorf("expiresAt = %v, want about %v", expiresAt, want)
+			}
+
+			got, err := provider.Parse(t.Context(), token, tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != testIdentity {
+				t.Errorf("Parse = %+v, want %+v", *got, testIdentity)
+			}
+		})
+	}
+}
+
+func TestJwtTokenProviderRejects(t *testing.T) {
+	provider := NewJwtTokenProvider(testSecret, "go-hex", 15*time.Minute, 24*time.Hour)
+	issue := func(t *testing.T, provider *JwtTokenProvider, kind entities.TokenKind) string {
+		t.Helper()
+		token, _, err := provider.Issue(t.Context(), testIdentity, kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	now := time.Now()
+	valid := func(subject string) jwtClaims {
+		return jwtClaims{
+			Email: testIdentity.Email,
+			Role:  testIdentity.Role,
+			Kind:  entities.AccessToken,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "go-hex",
+				Subject:   subject,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		token func(t *testing.T) string
+		kind  entities.TokenKind
+		want  string
+	}{
+		{
+			name:  "refresh token used as access token",
+			token: func(t *testing.T) string { return issue(t, provider, entities.RefreshToken) },
+			kind:  entities.AccessToken,
+			want:  "is a refresh token, want access",
+		},
+		{
+			name:  "access token used as refresh token",
+			token: func(t *testing.T) string { return issue(t, provider, entities.AccessToken) },
+			kind:  entities.RefreshToken,
+			want:  "is a access token, want refresh",
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return issue(t, NewJwtTokenProvider(testSecret, "go-hex", -time.Minute, time.Hour), entities.AccessToken)
+			},
+			kind: entities.AccessToken,
+			want: "expired",
+		},
+		{
+			name: "no expiry",
+			token: func(t *testing.T) string {
+				claims := valid("7")
+				claims.ExpiresAt = nil
+				return sign(t, jwt.SigningMethodHS256, testSecret, claims)
+			},
+			kind: entities.AccessToken,
+			want: "exp claim is required",
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				return issue(t, NewJwtTokenProvider(testSecret, "someone-else", time.Minute, time.Hour), entities.AccessToken)
+			},
+			kind: entities.AccessToken,
+			want: "invalid issuer",
+		},
+		{
+			name: "wrong secret",
+			token: func(t *testing.T) string {
+				return issue(t, NewJwtTokenProvider([]byte(strings.Repeat("x", 32)), "go-hex", time.Minute, time.Hour), entities.AccessToken)
+			},
+			kind: entities.AccessToken,
+			want: "signature is invalid",
+		},
+		{
+			name:  "tampered payload",
+			token: func(t *testing.T) string { return tamper(t, issue(t, provider, entities.AccessToken)) },
+			kind:  entities.AccessToken,
+			want:  "signature is invalid",
+		},
+		{
+			name:  "HS512 with the right secret",
+			token: func(t *testing.T) string { return sign(t, jwt.SigningMethodHS512, testSecret, valid("7")) },
+			kind:  entities.AccessToken,
+			want:  "signing method HS512 is invalid",
+		},
+		{
+			name: "unsigned",
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid("7"))
+			},
+			kind: entities.AccessToken,
+			want: "signing method none is invalid",
+		},
+		{
+			name:  "subject is not a number",
+			token: func(t *testing.T) string { return sign(t, jwt.SigningMethodHS256, testSecret, valid("alice")) },
+			kind:  entities.AccessToken,
+			want:  "invalid subject",
+		},
+		{
+			name:  "subject is zero",
+			token: func(t *testing.T) string { return sign(t, jwt.SigningMethodHS256, testSecret, valid("0")) },
+			kind:  entities.AccessToken,
+			want:  "invalid subject",
+		},
+		{
+			name:  "subject is negative",
+			token: func(t *testing.T) string { return sign(t, jwt.SigningMethodHS256, testSecret, valid("-7")) },
+			kind:  entities.AccessToken,
+			want:  "invalid subject",
+		},
+		{
+			name:  "subject is missing",
+			token: func(t *testing.T) string { return sign(t, jwt.SigningMethodHS256, testSecret, valid("")) },
+			kind:  entities.AccessToken,
+			want:  "invalid subject",
+		},
+		{
+			name:  "not a token",
+			token: func(t *testing.T) string { return "not-a-token" },
+			kind:  entities.AccessToken,
+			want:  "malformed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := provider.Parse(t.Context(), tt.token(t), tt.kind)
+			if err == nil {
+				t.Fatalf("Parse = %+v, want an error", identity)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %q, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwtClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// tamper swaps the payload of token for one claiming another role, keeping the
+// original signature.
+func tamper(t *testing.T, token string) string {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	forged := strings.Split(sign(t, jwt.SigningMethodHS256, []byte("forger"), jwtClaims{
+		Role: entities.RoleAdmin,
+		Kind: entities.AccessToken,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "go-hex",
+			Subject:   "7",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}), ".")
+	return parts[0] + "." + forged[1] + "." + parts[2]
+}
@@ -0,0 +1,91 @@
+package adapter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/adapter/httpx"
+	port "github.com/wittawat/go-hex/core/port/cart"
+)
+
+type HttpCartHandler struct {
+	service port.CartService
+}
+
+func NewHttpCartHandler(service port.CartService) *HttpCartHandler {
+	return &HttpCartHandler{service: service}
+}
+
+func (h *HttpCartHandler) GetCart(c *gin.Context) {
+	cart, err := h.service.Get(c.Request.Context())
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Get cart successfully", cart)
+}
+
+func (h *HttpCartHandler) AddItem(c *gin.Context) {
+	var req AddCartItemRequest
+	if !httpx.BindJSON(c, &req) {
+		return
+	}
+	cart, err := h.service.AddItem(c.Request.Context(), int(req.ProductId), req.Quantity)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Added item to cart successfully", cart)
+}
+
+func (h *HttpCartHandler) UpdateItem(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		httpx.BadRequest(c, "Invalid product id")
+		return
+	}
+	var req UpdateCartItemRequest
+	if !httpx.BindJSON(c, &req) {
+		return
+	}
+	cart, err := h.service.UpdateItem(c.Request.Context(), productId, req.Quantity)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Updated cart item successfully", cart)
+}
+
+func (h *HttpCartHandler) RemoveItem(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		httpx.BadRequest(c, "Invalid product id")
+		return
+	}
+	cart, err := h.service.RemoveItem(c.Request.Context(), productId)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Removed item from cart successfully", cart)
+}
+
+func (h *HttpCartHandler) ClearCart(c *gin.Context) {
+	if err := h.service.Clear(c.Request.Context()); err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Cleared cart successfully", nil)
+}
+
+func (h *HttpCartHandler) Checkout(c *gin.Context) {
+	order, err := h.service.Checkout(c.Request.Context())
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	c.Header("Location", fmt.Sprintf("/orders/%d", order.Id))
+	httpx.Respond(c, http.StatusCreated, "Created order successfully", order)
+}
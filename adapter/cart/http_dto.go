@@ -0,0 +1,10 @@
+package adapter
+
+type AddCartItemRequest struct {
+	ProductId uint `json:"product_id" binding:"required"`
+	Quantity  uint `json:"quantity" binding:"required,min=1,max=1000"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity uint `json:"quantity" binding:"required,min=1,max=1000"`
+}
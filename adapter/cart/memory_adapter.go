@@ -0,0 +1,101 @@
+package adapter
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/memtx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type MemoryCartRepository struct {
+	mu    sync.RWMutex
+	carts map[int][]entities.CartItem
+}
+
+func NewMemoryCartRepository() *MemoryCartRepository {
+	return &MemoryCartRepository{carts: make(map[int][]entities.CartItem)}
+}
+
+func (r *MemoryCartRepository) FindByUserId(ctx context.Context, userId int) ([]entities.CartItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.carts[userId]), nil
+}
+
+func (r *MemoryCartRepository) SetItem(ctx context.Context, userId int, productId int, quantity uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := slices.Clone(r.carts[userId])
+	if i := indexOf(items, productId); i >= 0 {
+		old := items[i]
+		items[i].Quantity = quantity
+		memtx.OnRollbackLocked(ctx, &r.mu, func() { r.restoreItem(userId, old, quantity) })
+	} else {
+		items = append(items, entities.CartItem{ProductId: uint(productId), Quantity: quantity, AddedAt: time.Now().UTC().Truncate(time.Microsecond)})
+		memtx.OnRollbackLocked(ctx, &r.mu, func() { r.dropItem(userId, productId, quantity) })
+	}
+	r.carts[userId] = items
+	return nil
+}
+
+func (r *MemoryCartRepository) RemoveItem(ctx context.Context, userId int, productId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := indexOf(r.carts[userId], productId)
+	if i < 0 {
+		return entities.NotFound("cart item not found")
+	}
+	old := r.carts[userId][i]
+	r.carts[userId] = slices.Delete(slices.Clone(r.carts[userId]), i, i+1)
+	memtx.OnRollbackLocked(ctx, &r.mu, func() { r.restoreItem(userId, old, 0) })
+	return nil
+}
+
+func (r *MemoryCartRepository) Clear(ctx context.Context, userId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.carts[userId]
+	delete(r.carts, userId)
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		for _, item := range old {
+			r.restoreItem(userId, item, 0)
+		}
+	})
+	return nil
+}
+
+// restoreItem puts old back into the cart of userId, if its line is absent or
+// still holds quantity, where 0 means it should be absent. Callers hold r.mu.
+func (r *MemoryCartRepository) restoreItem(userId int, old entities.CartItem, quantity uint) {
+	items := slices.Clone(r.carts[userId])
+	switch i := indexOf(items, int(old.ProductId)); {
+	case i < 0 && quantity == 0:
+		// back in the place it was added in
+		at := slices.IndexFunc(items, func(item entities.CartItem) bool { return item.AddedAt.After(old.AddedAt) })
+		if at < 0 {
+			at = len(items)
+		}
+		items = slices.Insert(items, at, old)
+	case i >= 0 && items[i].Quantity == quantity:
+		items[i] = old
+	default:
+		return
+	}
+	r.carts[userId] = items
+}
+
+// dropItem removes the line for productId from the cart of userId if it still
+// holds quantity. Callers hold r.mu.
+func (r *MemoryCartRepository) dropItem(userId int, productId int, quantity uint) {
+	items := r.carts[userId]
+	if i := indexOf(items, productId); i >= 0 && items[i].Quantity == quantity {
+		r.carts[userId] = slices.Delete(slices.Clone(items), i, i+1)
+	}
+}
+
+func indexOf(items []entities.CartItem, productId int) int {
+	return slices.IndexFunc(items, func(item entities.CartItem) bool { return item.ProductId == uint(productId) })
+}
@@ -0,0 +1,20 @@
+package adapter_test
+
+import (
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/cart"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/port/contract"
+)
+
+func TestMemoryCartRepository(t *testing.T) {
+	contract.RunCartRepository(t, func(t *testing.T) contract.CartStores {
+		return contract.CartStores{
+			Carts:    adapter.NewMemoryCartRepository(),
+			Users:    userAdapter.NewMemoryUserRepository(),
+			Products: productAdapter.NewMemoryProductRepository(),
+		}
+	})
+}
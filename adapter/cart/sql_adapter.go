@@ -0,0 +1,46 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type SqlCartRepository struct {
+	db     *sql.DB
+	upsert string
+}
+
+// NewSqlCartRepository serves MySQL or SQLite, as named by dialect.
+func NewSqlCartRepository(db *sql.DB, dialect string) *SqlCartRepository {
+	upsert, ok := upsertItem[dialect]
+	if !ok {
+		panic(fmt.Sprintf("cart: no upsert statement for %q", dialect))
+	}
+	return &SqlCartRepository{db: db, upsert: upsert}
+}
+
+func (r *SqlCartRepository) FindByUserId(ctx context.Context, userId int) ([]entities.CartItem, error) {
+	return findItems(ctx, sqltx.From(ctx, r.db), userId)
+}
+
+func (r *SqlCartRepository) SetItem(ctx context.Context, userId int, productId int, quantity uint) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if _, err := sqltx.From(ctx, r.db).ExecContext(ctx, r.upsert, userId, productId, quantity, now, now); err != nil {
+		return sqlerr.Translate(err, "cart item")
+	}
+	return nil
+}
+
+func (r *SqlCartRepository) RemoveItem(ctx context.Context, userId int, productId int) error {
+	return removeItem(ctx, sqltx.From(ctx, r.db), userId, productId)
+}
+
+func (r *SqlCartRepository) Clear(ctx context.Context, userId int) error {
+	return clearItems(ctx, sqltx.From(ctx, r.db), userId)
+}
@@ -0,0 +1,26 @@
+package adapter_test
+
+import (
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/cart"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/port/contract"
+	"github.com/wittawat/go-hex/db/dbtest"
+)
+
+func TestSqlCartRepository(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			contract.RunCartRepository(t, func(t *testing.T) contract.CartStores {
+				db := dialect.Open(t)
+				return contract.CartStores{
+					Carts:    adapter.NewSqlCartRepository(db, dialect.Name),
+					Users:    userAdapter.NewSqlUserRepository(db),
+					Products: productAdapter.NewSqlProductRepository(db),
+				}
+			})
+		})
+	}
+}
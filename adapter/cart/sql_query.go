@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/config"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Helpers of SqlCartRepository, written in SQL that MySQL and SQLite both accept
+// unless noted
+
+// upsertItem inserts a line or overwrites its quantity, the one statement
+// whose syntax differs between the dialects.
+var upsertItem = map[string]string{
+	config.StorageMySQL: "INSERT INTO cart_items (user_id, product_id, quantity, created_at, updated_at) VALUES (?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE quantity = VALUES(quantity), updated_at = VALUES(updated_at)",
+	config.StorageSQLite: "INSERT INTO cart_items (user_id, product_id, quantity, created_at, updated_at) VALUES (?, ?, ?, ?, ?) " +
+		"ON CONFLICT (user_id, product_id) DO UPDATE SET quantity = excluded.quantity, updated_at = excluded.updated_at",
+}
+
+func findItems(ctx context.Context, q sqltx.Querier, userId int) ([]entities.CartItem, error) {
+	rows, err := q.QueryContext(ctx, "SELECT product_id, quantity, created_at FROM cart_items WHERE user_id=? ORDER BY id", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []entities.CartItem
+	for rows.Next() {
+		var item entities.CartItem
+		if err := rows.Scan(&item.ProductId, &item.Quantity, &item.AddedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func removeItem(ctx context.Context, q sqltx.Querier, userId int, productId int) error {
+	result, err := q.ExecContext(ctx, "DELETE FROM cart_items WHERE user_id=? AND product_id=?", userId, productId)
+	return sqlerr.Affected(result, err, "cart item")
+}
+
+func clearItems(ctx context.Context, q sqltx.Querier, userId int) error {
+	_, err := q.ExecContext(ctx, "DELETE FROM cart_items WHERE user_id=?", userId)
+	return err
+}
@@ -0,0 +1,94 @@
+package adapter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CheckFunc reports whether one dependency can serve requests right now.
+type CheckFunc func(ctx context.Context) error
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Report is the outcome of one run of every registered check.
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Checker runs the readiness checks of the dependencies registered with it.
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+// NewChecker returns a Checker that gives each check timeout to answer.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: make(map[string]CheckFunc)}
+}
+
+// Register adds a check under name, replacing any check already there.
+func (c *Checker) Register(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Run runs every check concurrently and reports down if any of them failed.
+// Results are sorted by name.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	results := make([]CheckResult, 0, len(c.checks))
+	checks := make([]CheckFunc, 0, len(c.checks))
+	for name, check := range c.checks {
+		results = append(results, CheckResult{Name: name})
+		checks = append(checks, check)
+	}
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, results[i].Name, checks[i])
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, name string, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{Name: name, Status: StatusUp, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status, result.Error = StatusDown, err.Error()
+	}
+	return result
+}
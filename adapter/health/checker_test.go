@@ -0,0 +1,88 @@
+package adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	adapter "github.com/wittawat/go-hex/adapter/health"
+)
+
+func passing(context.Context) error { return nil }
+
+// hanging answers only once its context is done.
+func hanging(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCheckerRunReportsSlowCheckDown(t *testing.T) {
+	checker := adapter.NewChecker(20 * time.Millisecond)
+	checker.Register("sql", passing)
+	checker.Register("gateway", hanging)
+
+	start := time.Now()
+	report := checker.Run(t.Context())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Run took %v, want it cut short by the check timeout", elapsed)
+	}
+
+	if report.Status != adapter.StatusDown {
+		t.Errorf("status = %s, want %s", report.Status, adapter.StatusDown)
+	}
+	if len(report.Checks) != 2 {
+		t.Fatalf("report has %d checks, want 2: %+v", len(report.Checks), report.Checks)
+	}
+	gateway, sql := report.Checks[0], report.Checks[1]
+	if gateway.Name != "gateway" || sql.Name != "sql" {
+		t.Fatalf("checks are %s, %s, want them sorted by name", gateway.Name, sql.Name)
+	}
+	if gateway.Status != adapter.StatusDown || gateway.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("gateway = %+v, want down with %q", gateway, context.DeadlineExceeded)
+	}
+	if sql.Status != adapter.StatusUp || sql.Error != "" {
+		t.Errorf("sql = %+v, want up without an error", sql)
+	}
+}
+
+func TestHttpHealthHandlerReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		check      adapter.CheckFunc
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "every check up", check: passing, wantCode: http.StatusOK, wantStatus: adapter.StatusUp},
+		{name: "a check timing out", check: hanging, wantCode: http.StatusServiceUnavailable, wantStatus: adapter.StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := adapter.NewChecker(20 * time.Millisecond)
+			checker.Register("sql", passing)
+			checker.Register("gateway", tt.check)
+			app := gin.New()
+			app.GET("/readyz", adapter.NewHttpHealthHandler(checker).Ready)
+
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			var body struct {
+				Data adapter.Report `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode %s: %v", rec.Body, err)
+			}
+			if body.Data.Status != tt.wantStatus || len(body.Data.Checks) != 2 {
+				t.Errorf("report = %+v, want %s with both checks", body.Data, tt.wantStatus)
+			}
+		})
+	}
+}
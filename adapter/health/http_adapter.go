@@ -0,0 +1,33 @@
+package adapter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/adapter/httpx"
+)
+
+type HttpHealthHandler struct {
+	checker *Checker
+}
+
+func NewHttpHealthHandler(checker *Checker) *HttpHealthHandler {
+	return &HttpHealthHandler{checker: checker}
+}
+
+// Live answers as long as the process can serve HTTP at all; it checks no
+// dependency, so a database outage does not get the instance restarted.
+func (h *HttpHealthHandler) Live(c *gin.Context) {
+	httpx.Respond(c, http.StatusOK, "Alive", Report{Status: StatusUp, Checks: []CheckResult{}})
+}
+
+// Ready answers 200 when every registered dependency is reachable and 503
+// otherwise, with the status and latency of each check.
+func (h *HttpHealthHandler) Ready(c *gin.Context) {
+	report := h.checker.Run(c.Request.Context())
+	if report.Status != StatusUp {
+		httpx.Respond(c, http.StatusServiceUnavailable, "Not ready", report)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Ready", report)
+}
@@ -0,0 +1,13 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PingDB checks that db still accepts connections.
+func PingDB(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
@@ -0,0 +1,82 @@
+package httpx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+func init() {
+	// report fields by their JSON or query parameter names
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+					return name
+				}
+			}
+			return ""
+		})
+	}
+}
+
+// BindJSON decodes the body into req and checks its binding tags. On failure it
+// writes the error response and returns false: 400 for a body that is not valid
+// JSON, 422 with one detail per field for a body that breaks the rules.
+func BindJSON(c *gin.Context, req any) bool {
+	return bind(c, c.ShouldBindJSON(req), "Invalid JSON body")
+}
+
+// BindQuery is BindJSON for the query string.
+func BindQuery(c *gin.Context, req any) bool {
+	return bind(c, c.ShouldBindQuery(req), "Invalid query parameters")
+}
+
+func bind(c *gin.Context, err error, malformed string) bool {
+	if err == nil {
+		return true
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		BadRequest(c, malformed)
+		return false
+	}
+	var v entities.Violations
+	for _, fieldErr := range fieldErrs {
+		// the namespace starts with the struct name and names nested fields like "items[0].quantity"
+		_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+		v.Add(field, fieldMessage(fieldErr))
+	}
+	AbortWithError(c, v.Err())
+	return false
+}
+
+func fieldMessage(fieldErr validator.FieldError) string {
+	isString := fieldErr.Kind() == reflect.String
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	case "min":
+		if isString {
+			return "must be at least " + fieldErr.Param() + " characters"
+		}
+		return "must be at least " + fieldErr.Param()
+	case "max":
+		if isString {
+			return "must be at most " + fieldErr.Param() + " characters"
+		}
+		return "must be at most " + fieldErr.Param()
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	}
+	return "is invalid"
+}
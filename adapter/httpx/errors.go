@@ -0,0 +1,53 @@
+// Package httpx holds the gin helpers shared by every HTTP adapter.
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Status maps an error onto the HTTP status code for its kind.
+func Status(err error) int {
+	switch entities.KindOf(err) {
+	case entities.ErrNotFound:
+		return http.StatusNotFound
+	case entities.ErrConflict:
+		return http.StatusConflict
+	case entities.ErrValidation:
+		return http.StatusUnprocessableEntity
+	case entities.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case entities.ErrForbidden:
+		return http.StatusForbidden
+	case entities.ErrPaymentDeclined:
+		return http.StatusPaymentRequired
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// AbortWithError writes err as the error response and attaches err to c for
+// the access log. Errors outside the domain taxonomy are reported without
+// their details.
+func AbortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	status := Status(err)
+	body := ErrorBody{
+		Code:    string(entities.KindOf(err)),
+		Message: entities.Message(err),
+		Details: entities.Fields(err),
+	}
+	switch {
+	case status == http.StatusGatewayTimeout:
+		body.Code, body.Message = CodeTimeout, "Request timed out"
+	case body.Code == "":
+		body.Code, body.Message = CodeInternal, "Internal server error"
+	}
+	abort(c, status, body)
+}
@@ -0,0 +1,78 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+const (
+	RequestIdHeader = "X-Request-ID"
+	RequestIdKey    = "request_id"
+)
+
+// Envelope is the body of every JSON response. A success carries Data and
+// Message, plus Page for lists; a failure carries Error. Both carry the request id.
+type Envelope struct {
+	Data      any                `json:"data,omitempty"`
+	Page      *entities.PageInfo `json:"page,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	Error     *ErrorBody         `json:"error,omitempty"`
+	RequestId string             `json:"request_id,omitempty"`
+}
+
+type ErrorBody struct {
+	Code    string                `json:"code"`
+	Message string                `json:"message"`
+	Details []entities.FieldError `json:"details,omitempty"`
+}
+
+// Error codes for failures that have no domain kind
+const (
+	CodeBadRequest = "bad_request"
+	CodeTimeout    = "timeout"
+	CodeInternal   = "internal"
+)
+
+// Respond writes a successful response.
+func Respond(c *gin.Context, status int, message string, data any) {
+	c.JSON(status, Envelope{Data: nonNil(data), Message: message, RequestId: c.GetString(RequestIdKey)})
+}
+
+// RespondPage writes one page of a list.
+func RespondPage(c *gin.Context, message string, data any, page entities.PageInfo) {
+	c.JSON(http.StatusOK, Envelope{Data: nonNil(data), Page: &page, Message: message, RequestId: c.GetString(RequestIdKey)})
+}
+
+// BadRequest rejects a request the handler could not parse.
+func BadRequest(c *gin.Context, message string) {
+	abort(c, http.StatusBadRequest, ErrorBody{Code: CodeBadRequest, Message: message})
+}
+
+// NotFound answers requests for routes that do not exist.
+func NotFound(c *gin.Context) {
+	abort(c, http.StatusNotFound, ErrorBody{Code: string(entities.ErrNotFound), Message: "Route not found"})
+}
+
+// Recovered answers a request whose handler panicked, and attaches the panic
+// and its stack for the access log; see gin.CustomRecoveryWithWriter.
+func Recovered(c *gin.Context, recovered any) {
+	c.Error(fmt.Errorf("panic: %v\n%s", recovered, debug.Stack()))
+	abort(c, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: "Internal server error"})
+}
+
+// nonNil turns a nil slice into an empty one, so that clients can always iterate over list data.
+func nonNil(data any) any {
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
+
+func abort(c *gin.Context, status int, body ErrorBody) {
+	c.AbortWithStatusJSON(status, Envelope{Error: &body, RequestId: c.GetString(RequestIdKey)})
+}
@@ -0,0 +1,86 @@
+// Package logx builds the structured logger and carries request-scoped values,
+// such as the request id, from the context into every log line.
+package logx
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/wittawat/go-hex/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Redacted replaces the value of every sensitive attribute.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values never reach the output,
+// compared case-insensitively.
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"new_password":  true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+	"secret":        true,
+	"jwt_secret":    true,
+	"signature":     true,
+}
+
+// New returns a logger writing to w in the format and from the level of cfg.
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.SlogLevel(), ReplaceAttr: redact}
+	var handler slog.Handler
+	if cfg.Format == config.LogText {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
+
+type requestIdKey struct{}
+
+// WithRequestId returns ctx carrying the id of the request it serves.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// RequestId returns the request id ctx carries, or "".
+func RequestId(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request id and the current trace and span ids to
+// records logged with a context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestId(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
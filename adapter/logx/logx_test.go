@@ -0,0 +1,58 @@
+package logx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/logx"
+	"github.com/wittawat/go-hex/config"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	user := entities.User{Id: 7, Username: "alice", Email: "alice@example.com", Password: "$2a$10$storedhash", Role: entities.RoleCustomer}
+	tokens := entities.TokenPair{AccessToken: "access.jwt", RefreshToken: "refresh.jwt", TokenType: "Bearer", AccessExpiresAt: time.Now()}
+	secrets := []string{"hunter22", "Bearer header.jwt", user.Password, tokens.AccessToken, tokens.RefreshToken}
+
+	for _, format := range []string{config.LogJSON, config.LogText} {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := logx.New(config.LogConfig{Level: "debug", Format: format}, &out)
+			logger.With("password", "hunter22").Info("login",
+				"Authorization", "Bearer header.jwt", "user", user, "tokens", tokens)
+
+			line := out.String()
+			for _, secret := range secrets {
+				if strings.Contains(line, secret) {
+					t.Errorf("log line carries %q: %s", secret, line)
+				}
+			}
+			if got := strings.Count(line, logx.Redacted); got != 2 {
+				t.Errorf("log line has %d %s values, want 2: %s", got, logx.Redacted, line)
+			}
+			if !strings.Contains(line, user.Email) {
+				t.Errorf("log line lost the user's email: %s", line)
+			}
+		})
+	}
+}
+
+func TestLoggerAddsRequestId(t *testing.T) {
+	var out bytes.Buffer
+	logger := logx.New(config.LogConfig{Level: "info", Format: config.LogJSON}, &out)
+	logger.InfoContext(logx.WithRequestId(t.Context(), "req-42"), "handled")
+
+	var record map[string]any
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("decode %s: %v", out.String(), err)
+	}
+	if record["request_id"] != "req-42" {
+		t.Errorf("request_id = %v, want req-42 in %s", record["request_id"], out.String())
+	}
+	if _, ok := record["trace_id"]; ok {
+		t.Errorf("record outside a span carries a trace_id: %s", out.String())
+	}
+}
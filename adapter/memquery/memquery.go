@@ -0,0 +1,30 @@
+// Package memquery pages in-memory slices the way sqlquery pages SQL results.
+package memquery
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Page sorts items by compare, which orders ascending on the requested field
+// and breaks ties by id, and returns the requested page of them.
+func Page[T any](items []T, p entities.PageRequest, compare func(a, b T) int) ([]T, entities.PageInfo) {
+	slices.SortFunc(items, func(a, b T) int {
+		if p.Direction == entities.SortDesc {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+	total := len(items)
+	start := min(p.Offset, total)
+	end := min(start+p.Limit, total)
+	page := items[start:end]
+	return page, entities.NewPageInfo(p, len(page), total)
+}
+
+// Contains reports whether s contains substr, ignoring case like the SQL LIKE filters.
+func Contains(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
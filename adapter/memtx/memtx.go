@@ -0,0 +1,74 @@
+// Package memtx gives the in-memory repositories a unit of work. Writes are
+// applied as they happen and undone, newest first, if the unit fails. Units
+// run one at a time, but callers outside a unit may see its writes early and
+// write alongside them. An undo therefore reverses its own write only, by a
+// delta or a compare-and-set, rather than restoring an earlier copy that
+// would wipe out theirs.
+package memtx
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+type journal struct {
+	mu   sync.Mutex
+	undo []func()
+}
+
+type journalKey struct{}
+
+// OnRollback registers undo to run if the unit ctx belongs to fails. Outside a
+// unit it does nothing.
+func OnRollback(ctx context.Context, undo func()) {
+	j, ok := ctx.Value(journalKey{}).(*journal)
+	if !ok {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.undo = append(j.undo, undo)
+}
+
+// OnRollbackLocked is OnRollback for an undo that needs mu, the lock of the
+// repository it writes to.
+func OnRollbackLocked(ctx context.Context, mu sync.Locker, undo func()) {
+	OnRollback(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		undo()
+	})
+}
+
+// UnitOfWork is the unit of work port over the memory repositories.
+type UnitOfWork struct {
+	mu sync.Mutex
+}
+
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(journalKey{}).(*journal); ok {
+		return fn(ctx)
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	j := &journal{}
+	committed := false
+	defer func() {
+		if !committed {
+			for _, undo := range slices.Backward(j.undo) {
+				undo()
+			}
+		}
+	}()
+	if err := fn(context.WithValue(ctx, journalKey{}, j)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,120 @@
+package memtx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cartAdapter "github.com/wittawat/go-hex/adapter/cart"
+	"github.com/wittawat/go-hex/adapter/memtx"
+	paymentAdapter "github.com/wittawat/go-hex/adapter/payment"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	"github.com/wittawat/go-hex/core/entities"
+	"github.com/wittawat/go-hex/core/port/contract"
+)
+
+var errAbort = errors.New("abort")
+
+func TestUnitOfWork(t *testing.T) {
+	contract.RunUnitOfWork(t, func(t *testing.T) contract.UnitOfWorkStores {
+		products := productAdapter.NewMemoryProductRepository()
+		return contract.UnitOfWorkStores{Uow: memtx.NewUnitOfWork(), Products: products, Inventory: products}
+	})
+}
+
+// The tests below write through t.Context(), which carries no unit, while a
+// unit is running, as a request served alongside it would.
+
+func TestRollbackKeepsStockSetOutsideTheUnit(t *testing.T) {
+	uow := memtx.NewUnitOfWork()
+	products := productAdapter.NewMemoryProductRepository()
+	product := entities.Product{Title: "keyboard", Price: entities.Money{Amount: 129000, Currency: entities.THB}}
+	id, err := products.Save(t.Context(), &product)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := products.SetStock(t.Context(), id, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	err = uow.Do(t.Context(), func(ctx context.Context) error {
+		if err := products.Reserve(ctx, []entities.OrderItem{{ProductId: uint(id), Quantity: 2}}); err != nil {
+			return err
+		}
+		// restocked from 3 to 10, i.e. by 7
+		if err := products.SetStock(t.Context(), id, 10); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Do = %v, want the error fn returned", err)
+	}
+	got, err := products.FindById(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Stock != 12 {
+		t.Errorf("stock = %d, want the 2 reserved units back on top of the restock, 12", got.Stock)
+	}
+}
+
+func TestRollbackKeepsPaymentsSavedOutsideTheUnit(t *testing.T) {
+	uow := memtx.NewUnitOfWork()
+	payments := paymentAdapter.NewMemoryPaymentRepository()
+	amount := entities.Money{Amount: 129000, Currency: entities.THB}
+
+	err := uow.Do(t.Context(), func(ctx context.Context) error {
+		if _, err := payments.Save(ctx, &entities.Payment{OrderId: 1, Reference: "inside", Amount: amount, Status: entities.PaymentPending}); err != nil {
+			return err
+		}
+		if _, err := payments.Save(t.Context(), &entities.Payment{OrderId: 2, Reference: "outside", Amount: amount, Status: entities.PaymentPending}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Do = %v, want the error fn returned", err)
+	}
+	if _, err := payments.FindByReference(t.Context(), "inside"); !errors.Is(err, entities.ErrNotFound) {
+		t.Errorf("payment saved in the unit: %v, want ErrNotFound", err)
+	}
+	if _, err := payments.FindByReference(t.Context(), "outside"); err != nil {
+		t.Errorf("payment saved outside the unit: %v", err)
+	}
+}
+
+func TestRollbackKeepsCartLinesChangedOutsideTheUnit(t *testing.T) {
+	uow := memtx.NewUnitOfWork()
+	carts := cartAdapter.NewMemoryCartRepository()
+	const userId = 1
+	for productId, quantity := range map[int]uint{1: 1, 2: 1, 3: 1} {
+		if err := carts.SetItem(t.Context(), userId, productId, quantity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := uow.Do(t.Context(), func(ctx context.Context) error {
+		if err := carts.Clear(ctx, userId); err != nil {
+			return err
+		}
+		if err := carts.SetItem(t.Context(), userId, 2, 4); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Do = %v, want the error fn returned", err)
+	}
+	items, err := carts.FindByUserId(t.Context(), userId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[uint]uint)
+	for _, item := range items {
+		got[item.ProductId] = item.Quantity
+	}
+	if len(got) != 3 || got[1] != 1 || got[2] != 4 || got[3] != 1 {
+		t.Errorf("cart = %v, want products 1 and 3 back and product 2 at the 4 set outside the unit", got)
+	}
+}
@@ -0,0 +1,39 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/cart"
+)
+
+type cartRepository struct {
+	next    port.CartRepository
+	metrics *Metrics
+}
+
+// NewCartRepository records the latency and errors of every call to next.
+func NewCartRepository(next port.CartRepository, metrics *Metrics) port.CartRepository {
+	return &cartRepository{next: next, metrics: metrics}
+}
+
+func (r *cartRepository) FindByUserId(ctx context.Context, userId int) (_ []entities.CartItem, err error) {
+	defer r.metrics.observeCall("cart", "FindByUserId", time.Now(), &err)
+	return r.next.FindByUserId(ctx, userId)
+}
+
+func (r *cartRepository) SetItem(ctx context.Context, userId int, productId int, quantity uint) (err error) {
+	defer r.metrics.observeCall("cart", "SetItem", time.Now(), &err)
+	return r.next.SetItem(ctx, userId, productId, quantity)
+}
+
+func (r *cartRepository) RemoveItem(ctx context.Context, userId int, productId int) (err error) {
+	defer r.metrics.observeCall("cart", "RemoveItem", time.Now(), &err)
+	return r.next.RemoveItem(ctx, userId, productId)
+}
+
+func (r *cartRepository) Clear(ctx context.Context, userId int) (err error) {
+	defer r.metrics.observeCall("cart", "Clear", time.Now(), &err)
+	return r.next.Clear(ctx, userId)
+}
@@ -0,0 +1,103 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+const namespace = "gohex"
+
+// Metrics holds the collectors served on /metrics. It has its own registry,
+// so nothing registered globally by a library leaks into the output.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	callDuration    *prometheus.HistogramVec
+	callErrors      *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time to answer HTTP requests by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "outbound_call_duration_seconds",
+			Help:      "Time spent in outbound port calls (repositories, gateways) by port and method.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"port", "method"}),
+		callErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "outbound_call_errors_total",
+			Help:      "Failed outbound port calls by port, method and error kind.",
+		}, []string{"port", "method", "kind"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.requestDuration, m.callDuration, m.callErrors,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDB exports the pool statistics of db (open, in use and idle
+// connections, waits) labelled with name.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRequest records one answered HTTP request. route is the route
+// pattern, not the path, so that ids do not each get their own series.
+func (m *Metrics) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.requestDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// observeCall records one outbound port call that started at start and
+// failed with *err, if not nil. It is meant to be deferred.
+func (m *Metrics) observeCall(port, method string, start time.Time, err *error) {
+	m.callDuration.WithLabelValues(port, method).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		m.callErrors.WithLabelValues(port, method, errorKind(*err)).Inc()
+	}
+}
+
+// errorKind labels an error by its domain kind, so that expected outcomes
+// such as not_found can be told apart from database failures.
+func errorKind(err error) string {
+	var domainErr *entities.Error
+	switch {
+	case errors.As(err, &domainErr):
+		return string(domainErr.Kind)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	return "error"
+}
@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/order"
+)
+
+type orderRepository struct {
+	next    port.OrderRepository
+	metrics *Metrics
+}
+
+// NewOrderRepository records the latency and errors of every call to next.
+func NewOrderRepository(next port.OrderRepository, metrics *Metrics) port.OrderRepository {
+	return &orderRepository{next: next, metrics: metrics}
+}
+
+func (r *orderRepository) Save(ctx context.Context, order *entities.Order) (_ int, err error) {
+	defer r.metrics.observeCall("order", "Save", time.Now(), &err)
+	return r.next.Save(ctx, order)
+}
+
+func (r *orderRepository) FindById(ctx context.Context, id int) (_ *entities.Order, err error) {
+	defer r.metrics.observeCall("order", "FindById", time.Now(), &err)
+	return r.next.FindById(ctx, id)
+}
+
+func (r *orderRepository) FindByUserId(ctx context.Context, userId int) (_ []entities.Order, err error) {
+	defer r.metrics.observeCall("order", "FindByUserId", time.Now(), &err)
+	return r.next.FindByUserId(ctx, userId)
+}
+
+func (r *orderRepository) UpdateStatus(ctx context.Context, id int, from, to entities.OrderStatus) (err error) {
+	defer r.metrics.observeCall("order", "UpdateStatus", time.Now(), &err)
+	return r.next.UpdateStatus(ctx, id, from, to)
+}
+
+func (r *orderRepository) DeleteOne(ctx context.Context, id int) (err error) {
+	defer r.metrics.observeCall("order", "DeleteOne", time.Now(), &err)
+	return r.next.DeleteOne(ctx, id)
+}
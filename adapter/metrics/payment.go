@@ -0,0 +1,59 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/payment"
+)
+
+type paymentRepository struct {
+	next    port.PaymentRepository
+	metrics *Metrics
+}
+
+// NewPaymentRepository records the latency and errors of every call to next.
+func NewPaymentRepository(next port.PaymentRepository, metrics *Metrics) port.PaymentRepository {
+	return &paymentRepository{next: next, metrics: metrics}
+}
+
+func (r *paymentRepository) Save(ctx context.Context, payment *entities.Payment) (_ int, err error) {
+	defer r.metrics.observeCall("payment", "Save", time.Now(), &err)
+	return r.next.Save(ctx, payment)
+}
+
+func (r *paymentRepository) FindByReference(ctx context.Context, reference string) (_ *entities.Payment, err error) {
+	defer r.metrics.observeCall("payment", "FindByReference", time.Now(), &err)
+	return r.next.FindByReference(ctx, reference)
+}
+
+func (r *paymentRepository) FindByOrderId(ctx context.Context, orderId int) (_ []entities.Payment, err error) {
+	defer r.metrics.observeCall("payment", "FindByOrderId", time.Now(), &err)
+	return r.next.FindByOrderId(ctx, orderId)
+}
+
+func (r *paymentRepository) Settle(ctx context.Context, id int, result entities.PaymentResult) (err error) {
+	defer r.metrics.observeCall("payment", "Settle", time.Now(), &err)
+	return r.next.Settle(ctx, id, result)
+}
+
+func (r *paymentRepository) MarkRefundDue(ctx context.Context, id int) (err error) {
+	defer r.metrics.observeCall("payment", "MarkRefundDue", time.Now(), &err)
+	return r.next.MarkRefundDue(ctx, id)
+}
+
+type paymentGateway struct {
+	next    port.PaymentGateway
+	metrics *Metrics
+}
+
+// NewPaymentGateway records the latency and errors of every call to next.
+func NewPaymentGateway(next port.PaymentGateway, metrics *Metrics) port.PaymentGateway {
+	return &paymentGateway{next: next, metrics: metrics}
+}
+
+func (g *paymentGateway) Authorize(ctx context.Context, payment entities.Payment) (_ entities.PaymentResult, err error) {
+	defer g.metrics.observeCall("payment_gateway", "Authorize", time.Now(), &err)
+	return g.next.Authorize(ctx, payment)
+}
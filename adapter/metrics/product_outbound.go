@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/product"
+)
+
+type productOutbound struct {
+	next    port.ProductOutbound
+	metrics *Metrics
+}
+
+// NewProductOutbound records the latency and errors of every call to next.
+func NewProductOutbound(next port.ProductOutbound, metrics *Metrics) port.ProductOutbound {
+	return &productOutbound{next: next, metrics: metrics}
+}
+
+func (r *productOutbound) Save(ctx context.Context, product *entities.Product) (_ int, err error) {
+	defer r.metrics.observeCall("product", "Save", time.Now(), &err)
+	return r.next.Save(ctx, product)
+}
+
+func (r *productOutbound) FindById(ctx context.Context, id int) (_ *entities.Product, err error) {
+	defer r.metrics.observeCall("product", "FindById", time.Now(), &err)
+	return r.next.FindById(ctx, id)
+}
+
+func (r *productOutbound) Find(ctx context.Context, query entities.ProductQuery) (_ []entities.Product, _ entities.PageInfo, err error) {
+	defer r.metrics.observeCall("product", "Find", time.Now(), &err)
+	return r.next.Find(ctx, query)
+}
+
+func (r *productOutbound) UpdateOne(ctx context.Context, product *entities.Product, id int) (err error) {
+	defer r.metrics.observeCall("product", "UpdateOne", time.Now(), &err)
+	return r.next.UpdateOne(ctx, product, id)
+}
+
+func (r *productOutbound) DeleteOne(ctx context.Context, id int) (err error) {
+	defer r.metrics.observeCall("product", "DeleteOne", time.Now(), &err)
+	return r.next.DeleteOne(ctx, id)
+}
+
+type inventoryOutbound struct {
+	next    port.InventoryOutbound
+	metrics *Metrics
+}
+
+// NewInventoryOutbound records the latency and errors of every call to next.
+func NewInventoryOutbound(next port.InventoryOutbound, metrics *Metrics) port.InventoryOutbound {
+	return &inventoryOutbound{next: next, metrics: metrics}
+}
+
+func (r *inventoryOutbound) Reserve(ctx context.Context, items []entities.OrderItem) (err error) {
+	defer r.metrics.observeCall("inventory", "Reserve", time.Now(), &err)
+	return r.next.Reserve(ctx, items)
+}
+
+func (r *inventoryOutbound) Release(ctx context.Context, items []entities.OrderItem) (err error) {
+	defer r.metrics.observeCall("inventory", "Release", time.Now(), &err)
+	return r.next.Release(ctx, items)
+}
+
+func (r *inventoryOutbound) SetStock(ctx context.Context, productId int, stock uint) (err error) {
+	defer r.metrics.observeCall("inventory", "SetStock", time.Now(), &err)
+	return r.next.SetStock(ctx, productId, stock)
+}
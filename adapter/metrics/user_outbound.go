@@ -0,0 +1,49 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/user"
+)
+
+type userOutbound struct {
+	next    port.UserOutbound
+	metrics *Metrics
+}
+
+// NewUserOutbound records the latency and errors of every call to next.
+func NewUserOutbound(next port.UserOutbound, metrics *Metrics) port.UserOutbound {
+	return &userOutbound{next: next, metrics: metrics}
+}
+
+func (r *userOutbound) Save(ctx context.Context, user *entities.User) (_ int, err error) {
+	defer r.metrics.observeCall("user", "Save", time.Now(), &err)
+	return r.next.Save(ctx, user)
+}
+
+func (r *userOutbound) FindById(ctx context.Context, id int) (_ *entities.User, err error) {
+	defer r.metrics.observeCall("user", "FindById", time.Now(), &err)
+	return r.next.FindById(ctx, id)
+}
+
+func (r *userOutbound) FindByEmail(ctx context.Context, email string) (_ *entities.User, err error) {
+	defer r.metrics.observeCall("user", "FindByEmail", time.Now(), &err)
+	return r.next.FindByEmail(ctx, email)
+}
+
+func (r *userOutbound) Find(ctx context.Context, query entities.UserQuery) (_ []entities.User, _ entities.PageInfo, err error) {
+	defer r.metrics.observeCall("user", "Find", time.Now(), &err)
+	return r.next.Find(ctx, query)
+}
+
+func (r *userOutbound) UpdateOne(ctx context.Context, user *entities.User, id int) (err error) {
+	defer r.metrics.observeCall("user", "UpdateOne", time.Now(), &err)
+	return r.next.UpdateOne(ctx, user, id)
+}
+
+func (r *userOutbound) DeleteOne(ctx context.Context, id int) (err error) {
+	defer r.metrics.observeCall("user", "DeleteOne", time.Now(), &err)
+	return r.next.DeleteOne(ctx, id)
+}
@@ -1,10 +1,12 @@
 package adapter
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/adapter/httpx"
 	"github.com/wittawat/go-hex/core/entities"
 	port "github.com/wittawat/go-hex/core/port/order"
 )
@@ -18,64 +20,114 @@ func NewHttpOrderHandler(service port.OrderService) *HttpOrderHandler {
 }
 
 func (h *HttpOrderHandler) CreateOrder(c *gin.Context) {
-	var order entities.Order
-	if err := c.ShouldBindJSON(&order); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req CreateOrderRequest
+	if !httpx.BindJSON(c, &req) {
 		return
 	}
-	if err := h.service.Create(&order); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	order := req.Order()
+	id, err := h.service.Create(c.Request.Context(), &order)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	c.Header("Location", fmt.Sprintf("/orders/%d", id))
+	httpx.Respond(c, http.StatusCreated, "Created order successfully", order)
+}
+
+func (h *HttpOrderHandler) GetOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		httpx.BadRequest(c, "Invalid order id")
+		return
+	}
+	order, err := h.service.GetById(c.Request.Context(), id)
+	if err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"message": "Created order successfully"})
+	httpx.Respond(c, http.StatusOK, "Get order successfully", order)
 }
 
 func (h *HttpOrderHandler) FindOrder(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("user_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		httpx.BadRequest(c, "Invalid user id")
 		return
 	}
 
-	orders, err := h.service.GetByUser(id)
+	orders, err := h.service.GetByUser(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		httpx.AbortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Get orders successfully", "orders": orders})
+	httpx.Respond(c, http.StatusOK, "Get orders successfully", orders)
 }
 
-func (h *HttpOrderHandler) UpdateOrder(c *gin.Context) {
+func (h *HttpOrderHandler) UpdateOrderStatus(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		httpx.BadRequest(c, "Invalid order id")
 		return
 	}
-	var order entities.Order
-	if err = c.ShouldBindJSON(&order); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req UpdateOrderStatusRequest
+	if !httpx.BindJSON(c, &req) {
 		return
 	}
 
-	if err = h.service.Update(&order, id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	order, err := h.service.UpdateStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Updated order successfully"})
+	httpx.Respond(c, http.StatusOK, "Updated order status successfully", order)
 }
 
 func (h *HttpOrderHandler) DeleteOrder(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		httpx.BadRequest(c, "Invalid order id")
 		return
 	}
-	if err = h.service.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err = h.service.Delete(c.Request.Context(), id); err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Deleted order successfully"})
+	httpx.Respond(c, http.StatusOK, "Deleted order successfully", nil)
+}
+
+// PayOrder answers 200 when the payment was authorised and 202 when the gateway
+// has yet to decide.
+func (h *HttpOrderHandler) PayOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		httpx.BadRequest(c, "Invalid order id")
+		return
+	}
+	payment, err := h.service.Pay(c.Request.Context(), id)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	if payment.Status == entities.PaymentPending {
+		httpx.Respond(c, http.StatusAccepted, "Payment is being processed", payment)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Paid order successfully", payment)
+}
+
+func (h *HttpOrderHandler) GetPayments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		httpx.BadRequest(c, "Invalid order id")
+		return
+	}
+	payments, err := h.service.GetPayments(c.Request.Context(), id)
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Get payments successfully", payments)
 }
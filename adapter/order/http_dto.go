@@ -0,0 +1,25 @@
+package adapter
+
+import "github.com/wittawat/go-hex/core/entities"
+
+// CreateOrderRequest has no user id; an order always belongs to the caller.
+type CreateOrderRequest struct {
+	Items []OrderItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type OrderItemRequest struct {
+	ProductId uint `json:"product_id" binding:"required"`
+	Quantity  uint `json:"quantity" binding:"required,min=1,max=1000"`
+}
+
+func (r *CreateOrderRequest) Order() entities.Order {
+	items := make([]entities.OrderItem, 0, len(r.Items))
+	for _, item := range r.Items {
+		items = append(items, entities.OrderItem{ProductId: item.ProductId, Quantity: item.Quantity})
+	}
+	return entities.Order{Items: items}
+}
+
+type UpdateOrderStatusRequest struct {
+	Status entities.OrderStatus `json:"status" binding:"required,oneof=pending paid shipped delivered cancelled"`
+}
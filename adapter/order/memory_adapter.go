@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/memtx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type MemoryOrderRepository struct {
+	mu         sync.RWMutex
+	nextId     int
+	nextItemId int
+	orders     map[int]entities.Order
+}
+
+func NewMemoryOrderRepository() *MemoryOrderRepository {
+	return &MemoryOrderRepository{orders: make(map[int]entities.Order)}
+}
+
+func (r *MemoryOrderRepository) Save(ctx context.Context, order *entities.Order) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	r.nextId++
+	order.Id, order.CreatedAt, order.UpdatedAt = r.nextId, now, now
+	for i := range order.Items {
+		r.nextItemId++
+		order.Items[i].Id = r.nextItemId
+	}
+	r.orders[order.Id] = copyOrder(*order)
+	id := order.Id
+	memtx.OnRollbackLocked(ctx, &r.mu, func() { delete(r.orders, id) })
+	return order.Id, nil
+}
+
+func (r *MemoryOrderRepository) FindById(ctx context.Context, id int) (*entities.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, entities.NotFound("order %d not found", id)
+	}
+	order = copyOrder(order)
+	return &order, nil
+}
+
+func (r *MemoryOrderRepository) FindByUserId(ctx context.Context, userId int) ([]entities.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]int, 0, len(r.orders))
+	for id, order := range r.orders {
+		if int(order.UserId) == userId {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	var orders []entities.Order
+	for _, id := range ids {
+		orders = append(orders, copyOrder(r.orders[id]))
+	}
+	return orders, nil
+}
+
+func (r *MemoryOrderRepository) UpdateStatus(ctx context.Context, id int, from, to entities.OrderStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[id]
+	if !ok {
+		return entities.NotFound("order %d not found", id)
+	}
+	if order.Status != from {
+		return entities.Conflict("order %d is %s, not %s", id, order.Status, from)
+	}
+	updatedAt := order.UpdatedAt
+	order.Status, order.UpdatedAt = to, time.Now().UTC().Truncate(time.Microsecond)
+	r.orders[id] = order
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		if current, ok := r.orders[id]; ok && current.Status == to {
+			current.Status, current.UpdatedAt = from, updatedAt
+			r.orders[id] = current
+		}
+	})
+	return nil
+}
+
+func (r *MemoryOrderRepository) DeleteOne(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exist, ok := r.orders[id]
+	if !ok {
+		return entities.NotFound("order %d not found", id)
+	}
+	delete(r.orders, id)
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		if _, ok := r.orders[id]; !ok {
+			r.orders[id] = exist
+		}
+	})
+	return nil
+}
+
+// copyOrder keeps callers from sharing the stored items slice.
+func copyOrder(order entities.Order) entities.Order {
+	order.Items = slices.Clone(order.Items)
+	return order
+}
@@ -0,0 +1,20 @@
+package adapter_test
+
+import (
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/order"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/port/contract"
+)
+
+func TestMemoryOrderRepository(t *testing.T) {
+	contract.RunOrderRepository(t, func(t *testing.T) contract.OrderStores {
+		return contract.OrderStores{
+			Orders:   adapter.NewMemoryOrderRepository(),
+			Users:    userAdapter.NewMemoryUserRepository(),
+			Products: productAdapter.NewMemoryProductRepository(),
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type SqlOrderRepository struct {
+	db *sql.DB
+}
+
+func NewSqlOrderRepository(db *sql.DB) *SqlOrderRepository {
+	return &SqlOrderRepository{db: db}
+}
+
+func (r *SqlOrderRepository) Save(ctx context.Context, order *entities.Order) (int, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	var id int64
+	err := sqltx.Do(ctx, r.db, func(ctx context.Context) error {
+		query := "INSERT INTO orders (user_id, status, total, currency, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"
+		result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, order.UserId, order.Status, order.Total.Amount, order.Total.Currency, now, now)
+		if err != nil {
+			return sqlerr.Translate(err, "order")
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		return insertItems(ctx, sqltx.From(ctx, r.db), id, order.Items)
+	})
+	if err != nil {
+		return 0, err
+	}
+	order.Id, order.CreatedAt, order.UpdatedAt = int(id), now, now
+	return order.Id, nil
+}
+
+func (r *SqlOrderRepository) FindById(ctx context.Context, id int) (*entities.Order, error) {
+	var order entities.Order
+	row := sqltx.From(ctx, r.db).QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE id=?", id)
+	if err := scanOrder(row, &order); err != nil {
+		return nil, sqlerr.Translate(err, "order")
+	}
+	orders := []entities.Order{order}
+	if err := attachItems(ctx, sqltx.From(ctx, r.db), orders, "order_id=?", id); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+func (r *SqlOrderRepository) FindByUserId(ctx context.Context, userId int) ([]entities.Order, error) {
+	rows, err := sqltx.From(ctx, r.db).QueryContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE user_id=? ORDER BY id", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var orders []entities.Order
+	for rows.Next() {
+		var order entities.Order
+		if err := scanOrder(rows, &order); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := attachItems(ctx, sqltx.From(ctx, r.db), orders, "order_id IN (SELECT id FROM orders WHERE user_id=?)", userId); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *SqlOrderRepository) UpdateStatus(ctx context.Context, id int, from, to entities.OrderStatus) error {
+	return updateStatus(ctx, sqltx.From(ctx, r.db), id, from, to)
+}
+
+// DeleteOne removes the order; its items go with it through ON DELETE CASCADE.
+func (r *SqlOrderRepository) DeleteOne(ctx context.Context, id int) error {
+	query := "DELETE FROM orders WHERE id=?"
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, id)
+	return sqlerr.Affected(result, err, "order")
+}
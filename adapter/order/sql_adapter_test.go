@@ -0,0 +1,26 @@
+package adapter_test
+
+import (
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/order"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/port/contract"
+	"github.com/wittawat/go-hex/db/dbtest"
+)
+
+func TestSqlOrderRepository(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			contract.RunOrderRepository(t, func(t *testing.T) contract.OrderStores {
+				db := dialect.Open(t)
+				return contract.OrderStores{
+					Orders:   adapter.NewSqlOrderRepository(db),
+					Users:    userAdapter.NewSqlUserRepository(db),
+					Products: productAdapter.NewSqlProductRepository(db),
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Helpers of SqlOrderRepository, written in SQL that MySQL and SQLite both accept
+
+const orderColumns = "id, user_id, status, total, currency, created_at, updated_at"
+
+func scanOrder(row interface{ Scan(dest ...any) error }, order *entities.Order) error {
+	return row.Scan(&order.Id, &order.UserId, &order.Status, &order.Total.Amount, &order.Total.Currency, &order.CreatedAt, &order.UpdatedAt)
+}
+
+// insertItems stores items under orderId and sets their ids.
+func insertItems(ctx context.Context, q sqltx.Querier, orderId int64, items []entities.OrderItem) error {
+	query := "INSERT INTO order_items (order_id, product_id, title, quantity, unit_price) VALUES (?, ?, ?, ?, ?)"
+	for i := range items {
+		item := &items[i]
+		result, err := q.ExecContext(ctx, query, orderId, item.ProductId, item.Title, item.Quantity, item.UnitPrice.Amount)
+		if err != nil {
+			return sqlerr.Translate(err, "order item")
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		item.Id = int(id)
+	}
+	return nil
+}
+
+// updateStatus changes the status only while it is still from, so two
+// concurrent transitions of the same order cannot both succeed.
+func updateStatus(ctx context.Context, q sqltx.Querier, id int, from, to entities.OrderStatus) error {
+	query := "UPDATE orders SET status=?, updated_at=? WHERE id=? AND status=?"
+	result, err := q.ExecContext(ctx, query, to, time.Now().UTC().Truncate(time.Microsecond), id, from)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n > 0 {
+		return err
+	}
+
+	var current entities.OrderStatus
+	if err := q.QueryRowContext(ctx, "SELECT status FROM orders WHERE id=?", id).Scan(&current); err != nil {
+		return sqlerr.Translate(err, "order")
+	}
+	return entities.Conflict("order %d is %s, not %s", id, current, from)
+}
+
+// attachItems loads the items whose order_id matches where and appends them to
+// their order in orders. Items are priced in the currency of their order.
+func attachItems(ctx context.Context, q sqltx.Querier, orders []entities.Order, where string, args ...any) error {
+	byId := make(map[int]*entities.Order, len(orders))
+	for i := range orders {
+		byId[orders[i].Id] = &orders[i]
+	}
+
+	query := "SELECT id, order_id, product_id, title, quantity, unit_price FROM order_items WHERE " + where + " ORDER BY id"
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item entities.OrderItem
+		var orderId int
+		if err := rows.Scan(&item.Id, &orderId, &item.ProductId, &item.Title, &item.Quantity, &item.UnitPrice.Amount); err != nil {
+			return err
+		}
+		if order, ok := byId[orderId]; ok {
+			item.UnitPrice.Currency = order.Total.Currency
+			order.Items = append(order.Items, item)
+		}
+	}
+	return rows.Err()
+}
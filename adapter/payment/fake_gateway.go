@@ -0,0 +1,68 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/payment"
+)
+
+// FakeOutcome is what the fake gateway answers to every authorisation.
+type FakeOutcome string
+
+const (
+	FakeApprove FakeOutcome = "approve"
+	FakeDecline FakeOutcome = "decline"
+	FakeTimeout FakeOutcome = "timeout"
+)
+
+func (o FakeOutcome) Valid() bool {
+	switch o {
+	case FakeApprove, FakeDecline, FakeTimeout:
+		return true
+	}
+	return false
+}
+
+// FakeGateway is an in-process PaymentGateway for development and tests. It
+// charges nobody; it waits latency and then gives the configured outcome.
+type FakeGateway struct {
+	mu      sync.RWMutex
+	outcome FakeOutcome
+	latency time.Duration
+}
+
+func NewFakeGateway(outcome FakeOutcome, latency time.Duration) *FakeGateway {
+	return &FakeGateway{outcome: outcome, latency: latency}
+}
+
+// SetOutcome changes the answer to later authorisations.
+func (g *FakeGateway) SetOutcome(outcome FakeOutcome) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.outcome = outcome
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, payment entities.Payment) (entities.PaymentResult, error) {
+	g.mu.RLock()
+	outcome, latency := g.outcome, g.latency
+	g.mu.RUnlock()
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return entities.PaymentResult{}, ctx.Err()
+	}
+
+	switch outcome {
+	case FakeDecline:
+		return entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentDeclined, Reason: "card declined"}, nil
+	case FakeTimeout:
+		return entities.PaymentResult{}, port.ErrGatewayTimeout
+	}
+	return entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentAuthorized}, nil
+}
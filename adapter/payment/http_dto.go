@@ -0,0 +1,14 @@
+package adapter
+
+import "github.com/wittawat/go-hex/core/entities"
+
+// PaymentCallbackRequest is the body the gateway posts to /payments/webhook.
+type PaymentCallbackRequest struct {
+	Reference string                 `json:"reference" binding:"required,max=64"`
+	Status    entities.PaymentStatus `json:"status" binding:"required,oneof=authorized declined"`
+	Reason    string                 `json:"reason" binding:"max=255"`
+}
+
+func (r *PaymentCallbackRequest) Result() entities.PaymentResult {
+	return entities.PaymentResult{Reference: r.Reference, Status: r.Status, Reason: r.Reason}
+}
@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/adapter/httpx"
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/order"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the callback body, keyed with
+// the secret shared with the gateway.
+const SignatureHeader = "X-Signature"
+
+// maxCallbackSize bounds the body read before its signature is checked.
+const maxCallbackSize = 64 << 10
+
+type HttpWebhookHandler struct {
+	service port.OrderService
+	secret  []byte
+}
+
+func NewHttpWebhookHandler(service port.OrderService, secret []byte) *HttpWebhookHandler {
+	return &HttpWebhookHandler{service: service, secret: secret}
+}
+
+// Callback applies a payment decision sent by the gateway. Gateways retry
+// callbacks, so a repeated one answers 200 like the first.
+func (h *HttpWebhookHandler) Callback(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCallbackSize))
+	if err != nil {
+		httpx.BadRequest(c, "Invalid request body")
+		return
+	}
+	if !hmac.Equal([]byte(c.GetHeader(SignatureHeader)), []byte(Sign(h.secret, body))) {
+		httpx.AbortWithError(c, entities.Unauthorized("invalid webhook signature"))
+		return
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	var req PaymentCallbackRequest
+	if !httpx.BindJSON(c, &req) {
+		return
+	}
+	payment, err := h.service.SettlePayment(c.Request.Context(), req.Result())
+	if err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Payment updated successfully", payment)
+}
+
+// Sign returns the signature the gateway sends with body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,103 @@
+package adapter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	adapter "github.com/wittawat/go-hex/adapter/payment"
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/order"
+)
+
+var webhookSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// settleRecorder is an OrderService that only takes payment decisions, and
+// remembers them.
+type settleRecorder struct {
+	port.OrderService
+	results []entities.PaymentResult
+}
+
+func (s *settleRecorder) SettlePayment(ctx context.Context, result entities.PaymentResult) (*entities.Payment, error) {
+	s.results = append(s.results, result)
+	return &entities.Payment{Reference: result.Reference, Status: result.Status, Reason: result.Reason}, nil
+}
+
+func TestHttpWebhookHandlerChecksSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const body = `{"reference":"pay_a","status":"authorized"}`
+	oversized := `{"reference":"pay_a","status":"authorized","reason":"` + strings.Repeat("x", 64<<10) + `"}`
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		want      int
+	}{
+		{name: "signed", body: body, signature: adapter.Sign(webhookSecret, []byte(body)), want: http.StatusOK},
+		{name: "unsigned", body: body, want: http.StatusUnauthorized},
+		{name: "signed with another secret", body: body, signature: adapter.Sign([]byte(strings.Repeat("x", 32)), []byte(body)), want: http.StatusUnauthorized},
+		{
+			name:      "body changed after signing",
+			body:      `{"reference":"pay_a","status":"declined"}`,
+			signature: adapter.Sign(webhookSecret, []byte(body)),
+			want:      http.StatusUnauthorized,
+		},
+		{name: "signature in upper case", body: body, signature: strings.ToUpper(adapter.Sign(webhookSecret, []byte(body))), want: http.StatusUnauthorized},
+		{name: "signature cut short", body: body, signature: adapter.Sign(webhookSecret, []byte(body))[:32], want: http.StatusUnauthorized},
+		{
+			name:      "signed but malformed",
+			body:      `{"reference":"pay_a","status":"refunded"}`,
+			signature: adapter.Sign(webhookSecret, []byte(`{"reference":"pay_a","status":"refunded"}`)),
+			want:      http.StatusUnprocessableEntity,
+		},
+		{
+			// only the first 64 KiB are read, and they do not match the signature
+			name:      "signed but too large",
+			body:      oversized,
+			signature: adapter.Sign(webhookSecret, []byte(oversized)),
+			want:      http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &settleRecorder{}
+			app := gin.New()
+			app.POST("/payments/webhook", adapter.NewHttpWebhookHandler(service, webhookSecret).Callback)
+
+			req := httptest.NewRequest(http.MethodPost, "/payments/webhook", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.signature != "" {
+				req.Header.Set(adapter.SignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want != http.StatusOK {
+				if len(service.results) != 0 {
+					t.Fatalf("rejected callback reached the service with %+v", service.results)
+				}
+				return
+			}
+			want := entities.PaymentResult{Reference: "pay_a", Status: entities.PaymentAuthorized}
+			if len(service.results) != 1 || service.results[0] != want {
+				t.Fatalf("service got %+v, want %+v", service.results, want)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 test case 2 of RFC 4231
+	got := adapter.Sign([]byte("Jefe"), []byte("what do ya want for nothing?"))
+	if want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"; got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/memtx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type MemoryPaymentRepository struct {
+	mu       sync.RWMutex
+	nextId   int
+	payments map[int]entities.Payment
+}
+
+func NewMemoryPaymentRepository() *MemoryPaymentRepository {
+	return &MemoryPaymentRepository{payments: make(map[int]entities.Payment)}
+}
+
+func (r *MemoryPaymentRepository) Save(ctx context.Context, payment *entities.Payment) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, exist := range r.payments {
+		if exist.Reference == payment.Reference {
+			return 0, entities.Conflict("payment %s already exists", payment.Reference)
+		}
+		// mirrors the unique index on pending payments per order
+		if exist.OrderId == payment.OrderId && exist.Status == entities.PaymentPending && payment.Status == entities.PaymentPending {
+			return 0, entities.Conflict("order %d already has pending payment %s", payment.OrderId, exist.Reference)
+		}
+	}
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	r.nextId++
+	payment.Id, payment.CreatedAt, payment.UpdatedAt = r.nextId, now, now
+	r.payments[payment.Id] = *payment
+	id := payment.Id
+	memtx.OnRollbackLocked(ctx, &r.mu, func() { delete(r.payments, id) })
+	return payment.Id, nil
+}
+
+func (r *MemoryPaymentRepository) FindByReference(ctx context.Context, reference string) (*entities.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, payment := range r.payments {
+		if payment.Reference == reference {
+			return &payment, nil
+		}
+	}
+	return nil, entities.NotFound("payment %s not found", reference)
+}
+
+func (r *MemoryPaymentRepository) FindByOrderId(ctx context.Context, orderId int) ([]entities.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var payments []entities.Payment
+	for _, payment := range r.payments {
+		if int(payment.OrderId) == orderId {
+			payments = append(payments, payment)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].Id < payments[j].Id })
+	return payments, nil
+}
+
+func (r *MemoryPaymentRepository) Settle(ctx context.Context, id int, result entities.PaymentResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payment, ok := r.payments[id]
+	if !ok {
+		return entities.NotFound("payment %d not found", id)
+	}
+	if payment.Status != entities.PaymentPending {
+		return entities.Conflict("payment %d is already %s", id, payment.Status)
+	}
+	exist := payment
+	payment.Status, payment.Reason, payment.UpdatedAt = result.Status, result.Reason, time.Now().UTC().Truncate(time.Microsecond)
+	r.payments[id] = payment
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		if current, ok := r.payments[id]; ok && current.Status == result.Status {
+			r.payments[id] = exist
+		}
+	})
+	return nil
+}
+
+func (r *MemoryPaymentRepository) MarkRefundDue(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payment, ok := r.payments[id]
+	if !ok {
+		return entities.NotFound("payment %d not found", id)
+	}
+	if payment.Status != entities.PaymentAuthorized {
+		return entities.Conflict("payment %d is %s, not %s", id, payment.Status, entities.PaymentAuthorized)
+	}
+	if payment.RefundDue {
+		return nil
+	}
+	exist := payment
+	payment.RefundDue, payment.UpdatedAt = true, time.Now().UTC().Truncate(time.Microsecond)
+	r.payments[id] = payment
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		if current, ok := r.payments[id]; ok && current.RefundDue {
+			current.RefundDue, current.UpdatedAt = false, exist.UpdatedAt
+			r.payments[id] = current
+		}
+	})
+	return nil
+}
@@ -0,0 +1,22 @@
+package adapter_test
+
+import (
+	"testing"
+
+	orderAdapter "github.com/wittawat/go-hex/adapter/order"
+	adapter "github.com/wittawat/go-hex/adapter/payment"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/port/contract"
+)
+
+func TestMemoryPaymentRepository(t *testing.T) {
+	contract.RunPaymentRepository(t, func(t *testing.T) contract.PaymentStores {
+		return contract.PaymentStores{
+			Payments: adapter.NewMemoryPaymentRepository(),
+			Orders:   orderAdapter.NewMemoryOrderRepository(),
+			Users:    userAdapter.NewMemoryUserRepository(),
+			Products: productAdapter.NewMemoryProductRepository(),
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type SqlPaymentRepository struct {
+	db *sql.DB
+}
+
+func NewSqlPaymentRepository(db *sql.DB) *SqlPaymentRepository {
+	return &SqlPaymentRepository{db: db}
+}
+
+func (r *SqlPaymentRepository) Save(ctx context.Context, payment *entities.Payment) (int, error) {
+	return savePayment(ctx, sqltx.From(ctx, r.db), payment)
+}
+
+func (r *SqlPaymentRepository) FindByReference(ctx context.Context, reference string) (*entities.Payment, error) {
+	return findByReference(ctx, sqltx.From(ctx, r.db), reference)
+}
+
+func (r *SqlPaymentRepository) FindByOrderId(ctx context.Context, orderId int) ([]entities.Payment, error) {
+	return findByOrderId(ctx, sqltx.From(ctx, r.db), orderId)
+}
+
+func (r *SqlPaymentRepository) Settle(ctx context.Context, id int, result entities.PaymentResult) error {
+	return settle(ctx, sqltx.From(ctx, r.db), id, result)
+}
+
+func (r *SqlPaymentRepository) MarkRefundDue(ctx context.Context, id int) error {
+	return markRefundDue(ctx, sqltx.From(ctx, r.db), id)
+}
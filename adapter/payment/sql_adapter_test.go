@@ -0,0 +1,65 @@
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+
+	orderAdapter "github.com/wittawat/go-hex/adapter/order"
+	adapter "github.com/wittawat/go-hex/adapter/payment"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/entities"
+	"github.com/wittawat/go-hex/core/port/contract"
+	"github.com/wittawat/go-hex/db/dbtest"
+)
+
+func TestSqlPaymentRepository(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			contract.RunPaymentRepository(t, func(t *testing.T) contract.PaymentStores {
+				db := dialect.Open(t)
+				return contract.PaymentStores{
+					Payments: adapter.NewSqlPaymentRepository(db),
+					Orders:   orderAdapter.NewSqlOrderRepository(db),
+					Users:    userAdapter.NewSqlUserRepository(db),
+					Products: productAdapter.NewSqlProductRepository(db),
+				}
+			})
+		})
+	}
+}
+
+// The service refuses to delete an order that has payments; the schema backs it
+// up, where the memory repositories cannot.
+func TestSqlPaymentsOutliveDeleteOfTheirOrder(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			db := dialect.Open(t)
+			users, products, orders, payments := userAdapter.NewSqlUserRepository(db), productAdapter.NewSqlProductRepository(db),
+				orderAdapter.NewSqlOrderRepository(db), adapter.NewSqlPaymentRepository(db)
+			price := entities.Money{Amount: 129000, Currency: entities.THB}
+			if _, err := users.Save(t.Context(), &entities.User{Username: "alice", Email: "alice@example.com", Password: "secret", Role: entities.RoleCustomer}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := products.Save(t.Context(), &entities.Product{Title: "keyboard", Price: price}); err != nil {
+				t.Fatal(err)
+			}
+			order := entities.Order{UserId: 1, Status: entities.OrderCancelled, Total: price,
+				Items: []entities.OrderItem{{ProductId: 1, Title: "keyboard", Quantity: 1, UnitPrice: price}}}
+			if _, err := orders.Save(t.Context(), &order); err != nil {
+				t.Fatal(err)
+			}
+			payment := entities.Payment{OrderId: uint(order.Id), Reference: "pay_a", Amount: price, Status: entities.PaymentAuthorized, RefundDue: true}
+			if _, err := payments.Save(t.Context(), &payment); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := orders.DeleteOne(t.Context(), order.Id); !errors.Is(err, entities.ErrConflict) {
+				t.Fatalf("DeleteOne of an order with a payment = %v, want ErrConflict", err)
+			}
+			if _, err := payments.FindByReference(t.Context(), "pay_a"); err != nil {
+				t.Fatalf("payment after DeleteOne of its order: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Helpers of SqlPaymentRepository, written in SQL that MySQL and SQLite both accept
+
+const paymentColumns = "id, order_id, reference, amount, currency, status, reason, refund_due, created_at, updated_at"
+
+func scanPayment(row interface{ Scan(dest ...any) error }, payment *entities.Payment) error {
+	return row.Scan(&payment.Id, &payment.OrderId, &payment.Reference, &payment.Amount.Amount, &payment.Amount.Currency, &payment.Status, &payment.Reason, &payment.RefundDue, &payment.CreatedAt, &payment.UpdatedAt)
+}
+
+func savePayment(ctx context.Context, q sqltx.Querier, payment *entities.Payment) (int, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	query := "INSERT INTO payments (order_id, reference, amount, currency, status, reason, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := q.ExecContext(ctx, query, payment.OrderId, payment.Reference, payment.Amount.Amount, payment.Amount.Currency, payment.Status, payment.Reason, now, now)
+	if err != nil {
+		return 0, sqlerr.Translate(err, "payment")
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	payment.Id, payment.CreatedAt, payment.UpdatedAt = int(id), now, now
+	return payment.Id, nil
+}
+
+func findByReference(ctx context.Context, q sqltx.Querier, reference string) (*entities.Payment, error) {
+	var payment entities.Payment
+	row := q.QueryRowContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE reference=?", reference)
+	if err := scanPayment(row, &payment); err != nil {
+		return nil, sqlerr.Translate(err, "payment")
+	}
+	return &payment, nil
+}
+
+func findByOrderId(ctx context.Context, q sqltx.Querier, orderId int) ([]entities.Payment, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+paymentColumns+" FROM payments WHERE order_id=? ORDER BY id", orderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var payments []entities.Payment
+	for rows.Next() {
+		var payment entities.Payment
+		if err := scanPayment(rows, &payment); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// settle changes a payment only while it is pending, so two decisions on the
+// same payment cannot both be recorded.
+func settle(ctx context.Context, q sqltx.Querier, id int, result entities.PaymentResult) error {
+	query := "UPDATE payments SET status=?, reason=?, updated_at=? WHERE id=? AND status=?"
+	res, err := q.ExecContext(ctx, query, result.Status, result.Reason, time.Now().UTC().Truncate(time.Microsecond), id, entities.PaymentPending)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n > 0 {
+		return err
+	}
+
+	var current entities.PaymentStatus
+	if err := q.QueryRowContext(ctx, "SELECT status FROM payments WHERE id=?", id).Scan(&current); err != nil {
+		return sqlerr.Translate(err, "payment")
+	}
+	return entities.Conflict("payment %d is already %s", id, current)
+}
+
+// markRefundDue flags a payment only while it is authorised, like settle.
+func markRefundDue(ctx context.Context, q sqltx.Querier, id int) error {
+	query := "UPDATE payments SET refund_due=?, updated_at=? WHERE id=? AND status=?"
+	res, err := q.ExecContext(ctx, query, true, time.Now().UTC().Truncate(time.Microsecond), id, entities.PaymentAuthorized)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n > 0 {
+		return err
+	}
+
+	var current entities.PaymentStatus
+	if err := q.QueryRowContext(ctx, "SELECT status FROM payments WHERE id=?", id).Scan(&current); err != nil {
+		return sqlerr.Translate(err, "payment")
+	}
+	return entities.Conflict("payment %d is %s, not %s", id, current, entities.PaymentAuthorized)
+}
@@ -1,11 +1,12 @@
 package adapter
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/wittawat/go-hex/core/entities"
+	"github.com/wittawat/go-hex/adapter/httpx"
 	port "github.com/wittawat/go-hex/core/port/product"
 )
 
@@ -18,86 +19,110 @@ func NewHttpProductHandler(ib port.ProductInbound) *HttpProductHandler {
 }
 
 func (h *HttpProductHandler) CreateProduct(c *gin.Context) {
-	var product entities.Product
-	if err := c.ShouldBindJSON(&product); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+	var req CreateProductRequest
+	if !httpx.BindJSON(c, &req) {
 		return
 	}
-	if err := h.ib.Save(&product); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error})
+	product := req.Product()
+	id, err := h.ib.Save(c.Request.Context(), &product)
+	if err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"message": "Created product successfully"})
+	c.Header("Location", fmt.Sprintf("/products/%d", id))
+	httpx.Respond(c, http.StatusCreated, "Created product successfully", product)
 }
 
 func (h *HttpProductHandler) GetAllProduct(c *gin.Context) {
-	products, err := h.ib.Find()
+	var req ListProductsRequest
+	if !httpx.BindQuery(c, &req) {
+		return
+	}
+	products, page, err := h.ib.Find(c.Request.Context(), req.Query())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error})
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Get all product successfully", "products": products})
+	httpx.RespondPage(c, "Get products successfully", products, page)
 }
 
 func (h *HttpProductHandler) GetProduct(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product id"})
+		httpx.BadRequest(c, "Invalid product id")
 		return
 	}
-	product, err := h.ib.FindById(id)
+	product, err := h.ib.FindById(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error})
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Get all product successfully", "product": product})
+	httpx.Respond(c, http.StatusOK, "Get product successfully", product)
 }
 
 func (h *HttpProductHandler) UpdateProduct(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product id"})
+		httpx.BadRequest(c, "Invalid product id")
 		return
 	}
 
-	var product entities.Product
-	if err = c.ShouldBindJSON(&product); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON input"})
+	var req UpdateProductRequest
+	if !httpx.BindJSON(c, &req) {
 		return
 	}
+	product := req.Product()
 
-	existProduct, err := h.ib.FindById(id)
+	existProduct, err := h.ib.FindById(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		httpx.AbortWithError(c, err)
 		return
 	}
 
 	if product.Title == "" {
 		product.Title = existProduct.Title
 	}
-	if product.Price == 0 {
+	if product.Price.IsZero() {
 		product.Price = existProduct.Price
 	}
 	if product.Detail == "" {
 		product.Detail = existProduct.Detail
 	}
 
-	if err = h.ib.UpdateOne(&product, id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err = h.ib.UpdateOne(c.Request.Context(), &product, id); err != nil {
+		httpx.AbortWithError(c, err)
+		return
+	}
+	httpx.Respond(c, http.StatusOK, "Updated product successfully", nil)
+}
+
+func (h *HttpProductHandler) SetProductStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		httpx.BadRequest(c, "Invalid product id")
+		return
+	}
+
+	var req SetStockRequest
+	if !httpx.BindJSON(c, &req) {
+		return
+	}
+	if err = h.ib.SetStock(c.Request.Context(), id, *req.Stock); err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Updated product successfully"})
+	httpx.Respond(c, http.StatusOK, "Updated product stock successfully", nil)
 }
 
 func (h *HttpProductHandler) DeleteProduct(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product id"})
+		httpx.BadRequest(c, "Invalid product id")
 		return
 	}
-	if err = h.ib.DeleteOne(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err = h.ib.DeleteOne(c.Request.Context(), id); err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Deleted product successfully"})
+	httpx.Respond(c, http.StatusOK, "Deleted product successfully", nil)
 }
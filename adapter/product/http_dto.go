@@ -0,0 +1,66 @@
+package adapter
+
+import "github.com/wittawat/go-hex/core/entities"
+
+// MoneyRequest is a price in minor units, e.g. {"amount": 12950, "currency": "THB"} for 129.50 THB.
+type MoneyRequest struct {
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"required,oneof=THB USD"`
+}
+
+func (r *MoneyRequest) Money() entities.Money {
+	return entities.NewMoney(r.Amount, entities.Currency(r.Currency))
+}
+
+type CreateProductRequest struct {
+	Title  string       `json:"title" binding:"required,max=255"`
+	Price  MoneyRequest `json:"price" binding:"required"`
+	Detail string       `json:"detail"`
+	Stock  uint         `json:"stock"`
+}
+
+func (r *CreateProductRequest) Product() entities.Product {
+	return entities.Product{Title: r.Title, Price: r.Price.Money(), Detail: r.Detail, Stock: r.Stock}
+}
+
+// UpdateProductRequest is a partial update; empty fields keep their stored value.
+type UpdateProductRequest struct {
+	Title  string        `json:"title" binding:"omitempty,max=255"`
+	Price  *MoneyRequest `json:"price" binding:"omitempty"`
+	Detail string        `json:"detail"`
+}
+
+func (r *UpdateProductRequest) Product() entities.Product {
+	product := entities.Product{Title: r.Title, Detail: r.Detail}
+	if r.Price != nil {
+		product.Price = r.Price.Money()
+	}
+	return product
+}
+
+// SetStockRequest is the body of PUT /products/:id/stock; zero is a valid stock.
+type SetStockRequest struct {
+	Stock *uint `json:"stock" binding:"required"`
+}
+
+// ListProductsRequest holds the query parameters of GET /products.
+type ListProductsRequest struct {
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string `form:"cursor"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=id title price created_at"`
+	Order    string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Currency string `form:"currency" binding:"omitempty,oneof=THB USD"`
+	MinPrice int64  `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice int64  `form:"max_price" binding:"omitempty,min=0"`
+	Title    string `form:"title"`
+}
+
+func (r *ListProductsRequest) Query() entities.ProductQuery {
+	return entities.ProductQuery{
+		PageRequest:   entities.PageRequest{Limit: r.Limit, Cursor: r.Cursor, Sort: r.Sort, Direction: entities.SortDirection(r.Order)},
+		Currency:      entities.Currency(r.Currency),
+		MinPrice:      r.MinPrice,
+		MaxPrice:      r.MaxPrice,
+		TitleContains: r.Title,
+	}
+}
@@ -0,0 +1,180 @@
+package adapter
+
+import (
+	"cmp"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/memquery"
+	"github.com/wittawat/go-hex/adapter/memtx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type MemoryProductRepository struct {
+	mu       sync.RWMutex
+	nextId   int
+	products map[int]entities.Product
+}
+
+func NewMemoryProductRepository() *MemoryProductRepository {
+	return &MemoryProductRepository{products: make(map[int]entities.Product)}
+}
+
+func (r *MemoryProductRepository) Save(ctx context.Context, product *entities.Product) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	r.nextId++
+	product.Id, product.CreatedAt, product.UpdatedAt = r.nextId, now, now
+	r.products[product.Id] = *product
+	id := product.Id
+	memtx.OnRollbackLocked(ctx, &r.mu, func() { delete(r.products, id) })
+	return product.Id, nil
+}
+
+func (r *MemoryProductRepository) Find(ctx context.Context, query entities.ProductQuery) ([]entities.Product, entities.PageInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var products []entities.Product
+	for _, product := range r.products {
+		if query.Currency != "" && product.Price.Currency != query.Currency {
+			continue
+		}
+		if product.Price.Amount < query.MinPrice || query.MaxPrice > 0 && product.Price.Amount > query.MaxPrice {
+			continue
+		}
+		if query.TitleContains != "" && !memquery.Contains(product.Title, query.TitleContains) {
+			continue
+		}
+		products = append(products, product)
+	}
+	products, page := memquery.Page(products, query.PageRequest, compareProducts(query.Sort))
+	return products, page, nil
+}
+
+func (r *MemoryProductRepository) FindById(ctx context.Context, id int) (*entities.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	product, ok := r.products[id]
+	if !ok {
+		return nil, entities.NotFound("product %d not found", id)
+	}
+	return &product, nil
+}
+
+func (r *MemoryProductRepository) UpdateOne(ctx context.Context, product *entities.Product, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exist, ok := r.products[id]
+	if !ok {
+		return entities.NotFound("product %d not found", id)
+	}
+	product.Id, product.CreatedAt, product.UpdatedAt = id, exist.CreatedAt, time.Now().UTC().Truncate(time.Microsecond)
+	product.Stock = exist.Stock
+	r.products[id] = *product
+	written := *product
+	// puts the details back unless someone changed them since, and leaves the
+	// stock to its own undos
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		current, ok := r.products[id]
+		if ok && current.Title == written.Title && current.Price == written.Price && current.Detail == written.Detail {
+			current.Title, current.Price, current.Detail = exist.Title, exist.Price, exist.Detail
+			r.products[id] = current
+		}
+	})
+	return nil
+}
+
+func (r *MemoryProductRepository) DeleteOne(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exist, ok := r.products[id]
+	if !ok {
+		return entities.NotFound("product %d not found", id)
+	}
+	delete(r.products, id)
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		if _, ok := r.products[id]; !ok {
+			r.products[id] = exist
+		}
+	})
+	return nil
+}
+
+func (r *MemoryProductRepository) Reserve(ctx context.Context, items []entities.OrderItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, item := range items {
+		product, ok := r.products[int(item.ProductId)]
+		if !ok {
+			return entities.NotFound("product %d not found", item.ProductId)
+		}
+		if product.Stock < item.Quantity {
+			return entities.Conflict("insufficient stock for product %d: %d requested, %d available", item.ProductId, item.Quantity, product.Stock)
+		}
+	}
+	for _, item := range items {
+		product := r.products[int(item.ProductId)]
+		product.Stock -= item.Quantity
+		r.products[product.Id] = product
+		memtx.OnRollbackLocked(ctx, &r.mu, func() { r.shiftStock(product.Id, int(item.Quantity)) })
+	}
+	return nil
+}
+
+func (r *MemoryProductRepository) Release(ctx context.Context, items []entities.OrderItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, item := range items {
+		if product, ok := r.products[int(item.ProductId)]; ok {
+			product.Stock += item.Quantity
+			r.products[product.Id] = product
+			memtx.OnRollbackLocked(ctx, &r.mu, func() { r.shiftStock(product.Id, -int(item.Quantity)) })
+		}
+	}
+	return nil
+}
+
+func (r *MemoryProductRepository) SetStock(ctx context.Context, productId int, stock uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.products[productId]
+	if !ok {
+		return entities.NotFound("product %d not found", productId)
+	}
+	delta := int(product.Stock) - int(stock)
+	product.Stock, product.UpdatedAt = stock, time.Now().UTC().Truncate(time.Microsecond)
+	r.products[productId] = product
+	memtx.OnRollbackLocked(ctx, &r.mu, func() { r.shiftStock(productId, delta) })
+	return nil
+}
+
+// compareProducts orders products by one of entities.ProductSortFields, then by id.
+func compareProducts(field string) func(a, b entities.Product) int {
+	return func(a, b entities.Product) int {
+		var c int
+		switch field {
+		case "title":
+			c = strings.Compare(a.Title, b.Title)
+		case "price":
+			c = cmp.Compare(a.Price.Amount, b.Price.Amount)
+		case "created_at":
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c == 0 {
+			c = cmp.Compare(a.Id, b.Id)
+		}
+		return c
+	}
+}
+
+// shiftStock adds delta to the stock of product id, stopping at zero.
+// Callers hold r.mu.
+func (r *MemoryProductRepository) shiftStock(id int, delta int) {
+	if product, ok := r.products[id]; ok {
+		product.Stock = uint(max(int(product.Stock)+delta, 0))
+		r.products[id] = product
+	}
+}
@@ -0,0 +1,22 @@
+package adapter_test
+
+import (
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/product"
+	"github.com/wittawat/go-hex/core/port/contract"
+	port "github.com/wittawat/go-hex/core/port/product"
+)
+
+func TestMemoryProductRepository(t *testing.T) {
+	contract.RunProductOutbound(t, func(t *testing.T) port.ProductOutbound {
+		return adapter.NewMemoryProductRepository()
+	})
+}
+
+func TestMemoryInventory(t *testing.T) {
+	contract.RunInventoryOutbound(t, func(t *testing.T) contract.InventoryStores {
+		products := adapter.NewMemoryProductRepository()
+		return contract.InventoryStores{Products: products, Inventory: products}
+	})
+}
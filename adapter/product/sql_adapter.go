@@ -0,0 +1,108 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqlquery"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type SqlProductRepository struct {
+	db *sql.DB
+}
+
+func NewSqlProductRepository(db *sql.DB) *SqlProductRepository {
+	return &SqlProductRepository{db: db}
+}
+
+func (r *SqlProductRepository) Save(ctx context.Context, product *entities.Product) (int, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	query := "INSERT INTO products (title, price, currency, detail, stock, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, product.Title, product.Price.Amount, product.Price.Currency, product.Detail, product.Stock, now, now)
+	if err != nil {
+		return 0, sqlerr.Translate(err, "product")
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	product.Id, product.CreatedAt, product.UpdatedAt = int(id), now, now
+	return product.Id, nil
+}
+
+func (r *SqlProductRepository) Find(ctx context.Context, query entities.ProductQuery) ([]entities.Product, entities.PageInfo, error) {
+	where := productFilter(query)
+	page, pageArgs, err := sqlquery.Page(query.PageRequest, entities.ProductSortFields)
+	if err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+
+	var total int
+	if err := sqltx.From(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+where.String(), where.Args()...).Scan(&total); err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+
+	rows, err := sqltx.From(ctx, r.db).QueryContext(ctx, "SELECT id, title, price, currency, detail, stock, created_at, updated_at FROM products"+where.String()+page, slices.Concat(where.Args(), pageArgs)...)
+	if err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	defer rows.Close()
+	var products []entities.Product
+	for rows.Next() {
+		var product entities.Product
+		if err := rows.Scan(&product.Id, &product.Title, &product.Price.Amount, &product.Price.Currency, &product.Detail, &product.Stock, &product.CreatedAt, &product.UpdatedAt); err != nil {
+			return nil, entities.PageInfo{}, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	return products, entities.NewPageInfo(query.PageRequest, len(products), total), nil
+}
+
+func (r *SqlProductRepository) FindById(ctx context.Context, id int) (*entities.Product, error) {
+	var product entities.Product
+	query := "SELECT id, title, price, currency, detail, stock, created_at, updated_at FROM products WHERE id=? AND deleted_at IS NULL"
+	row := sqltx.From(ctx, r.db).QueryRowContext(ctx, query, id)
+	if err := row.Scan(&product.Id, &product.Title, &product.Price.Amount, &product.Price.Currency, &product.Detail, &product.Stock, &product.CreatedAt, &product.UpdatedAt); err != nil {
+		return nil, sqlerr.Translate(err, "product")
+	}
+	return &product, nil
+}
+
+func (r *SqlProductRepository) UpdateOne(ctx context.Context, product *entities.Product, id int) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	query := "UPDATE products SET title=?, price=?, currency=?, detail=?, updated_at=? WHERE id=? AND deleted_at IS NULL"
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, product.Title, product.Price.Amount, product.Price.Currency, product.Detail, now, id)
+	if err := sqlerr.Affected(result, err, "product"); err != nil {
+		return err
+	}
+	product.Id, product.UpdatedAt = id, now
+	return nil
+}
+
+func (r *SqlProductRepository) DeleteOne(ctx context.Context, id int) error {
+	// Order items still refer to the product, so it is only marked deleted.
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	query := "UPDATE products SET deleted_at=? WHERE id=? AND deleted_at IS NULL"
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, now, id)
+	return sqlerr.Affected(result, err, "product")
+}
+
+func (r *SqlProductRepository) Reserve(ctx context.Context, items []entities.OrderItem) error {
+	return reserveStock(ctx, r.db, items)
+}
+
+func (r *SqlProductRepository) Release(ctx context.Context, items []entities.OrderItem) error {
+	return releaseStock(ctx, r.db, items)
+}
+
+func (r *SqlProductRepository) SetStock(ctx context.Context, productId int, stock uint) error {
+	return setStock(ctx, r.db, productId, stock)
+}
@@ -0,0 +1,31 @@
+package adapter_test
+
+import (
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/product"
+	"github.com/wittawat/go-hex/core/port/contract"
+	port "github.com/wittawat/go-hex/core/port/product"
+	"github.com/wittawat/go-hex/db/dbtest"
+)
+
+func TestSqlProductRepository(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			contract.RunProductOutbound(t, func(t *testing.T) port.ProductOutbound {
+				return adapter.NewSqlProductRepository(dialect.Open(t))
+			})
+		})
+	}
+}
+
+func TestSqlInventory(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			contract.RunInventoryOutbound(t, func(t *testing.T) contract.InventoryStores {
+				products := adapter.NewSqlProductRepository(dialect.Open(t))
+				return contract.InventoryStores{Products: products, Inventory: products}
+			})
+		})
+	}
+}
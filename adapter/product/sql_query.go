@@ -0,0 +1,91 @@
+package adapter
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqlquery"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// productFilter is the WHERE clause shared by Find and its COUNT query.
+func productFilter(query entities.ProductQuery) sqlquery.Where {
+	var where sqlquery.Where
+	where.Add("deleted_at IS NULL")
+	if query.Currency != "" {
+		where.Add("currency = ?", query.Currency)
+	}
+	if query.MinPrice > 0 {
+		where.Add("price >= ?", query.MinPrice)
+	}
+	if query.MaxPrice > 0 {
+		where.Add("price <= ?", query.MaxPrice)
+	}
+	if query.TitleContains != "" {
+		where.Add("title LIKE ? ESCAPE '!'", sqlquery.Contains(query.TitleContains))
+	}
+	return where
+}
+
+// reserveStock takes the items out of stock in one transaction. Rows are
+// updated in product id order so that two reservations cannot deadlock.
+func reserveStock(ctx context.Context, db *sql.DB, items []entities.OrderItem) error {
+	sorted := slices.SortedFunc(slices.Values(items), func(a, b entities.OrderItem) int {
+		return cmp.Compare(a.ProductId, b.ProductId)
+	})
+	return sqltx.Do(ctx, db, func(ctx context.Context) error {
+		q := sqltx.From(ctx, db)
+		query := "UPDATE products SET stock = stock - ? WHERE id = ? AND stock >= ? AND deleted_at IS NULL"
+		for _, item := range sorted {
+			result, err := q.ExecContext(ctx, query, item.Quantity, item.ProductId, item.Quantity)
+			if err != nil {
+				return sqlerr.Translate(err, "product")
+			}
+			n, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return stockShortage(ctx, q, item)
+			}
+		}
+		return nil
+	})
+}
+
+// stockShortage explains why item could not be reserved.
+func stockShortage(ctx context.Context, q sqltx.Querier, item entities.OrderItem) error {
+	var stock uint
+	if err := q.QueryRowContext(ctx, "SELECT stock FROM products WHERE id = ? AND deleted_at IS NULL", item.ProductId).Scan(&stock); err != nil {
+		return sqlerr.Translate(err, "product")
+	}
+	return insufficientStock(item, stock)
+}
+
+func releaseStock(ctx context.Context, db *sql.DB, items []entities.OrderItem) error {
+	return sqltx.Do(ctx, db, func(ctx context.Context) error {
+		// A product deleted since the reservation has no stock to return to.
+		query := "UPDATE products SET stock = stock + ? WHERE id = ? AND deleted_at IS NULL"
+		for _, item := range items {
+			if _, err := sqltx.From(ctx, db).ExecContext(ctx, query, item.Quantity, item.ProductId); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func setStock(ctx context.Context, db *sql.DB, productId int, stock uint) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	result, err := sqltx.From(ctx, db).ExecContext(ctx, "UPDATE products SET stock = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL", stock, now, productId)
+	return sqlerr.Affected(result, err, "product")
+}
+
+func insufficientStock(item entities.OrderItem, stock uint) error {
+	return entities.Conflict("insufficient stock for product %d: %d requested, %d available", item.ProductId, item.Quantity, stock)
+}
@@ -0,0 +1,75 @@
+// Package sqlerr translates database/sql and driver errors into the core error
+// taxonomy, so the SQL repositories report the same kinds as the memory ones.
+package sqlerr
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/wittawat/go-hex/core/entities"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// MySQL server error numbers
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlRowIsReferenced = 1451
+	mysqlNoReferencedRow = 1452
+)
+
+// Translate maps err onto a domain error about record, e.g. "user". Errors it
+// does not recognise are returned unchanged.
+func Translate(err error, record string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return entities.NotFound("%s not found", record)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlDuplicateEntry:
+			return &entities.Error{Kind: entities.ErrConflict, Message: record + " already exists", Err: err}
+		case mysqlRowIsReferenced:
+			return &entities.Error{Kind: entities.ErrConflict, Message: record + " is still referenced", Err: err}
+		case mysqlNoReferencedRow:
+			return &entities.Error{Kind: entities.ErrValidation, Message: record + " references a missing record", Err: err}
+		}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return &entities.Error{Kind: entities.ErrConflict, Message: record + " already exists", Err: err}
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY, sqlite3.SQLITE_CONSTRAINT_TRIGGER:
+			// SQLite reports a missing parent and a still-referenced row alike,
+			// the latter as a trigger failure when the reference is ON DELETE RESTRICT
+			return &entities.Error{Kind: entities.ErrConflict, Message: record + " violates a reference", Err: err}
+		case sqlite3.SQLITE_CONSTRAINT_CHECK, sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+			return &entities.Error{Kind: entities.ErrValidation, Message: record + " is invalid", Err: err}
+		}
+	}
+	return err
+}
+
+// Affected turns an UPDATE or DELETE that matched no row into a not-found error.
+// MySQL connections must set clientFoundRows, otherwise an UPDATE that changes
+// nothing would count as missing.
+func Affected(result sql.Result, err error, record string) error {
+	if err != nil {
+		return Translate(err, record)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return entities.NotFound("%s not found", record)
+	}
+	return nil
+}
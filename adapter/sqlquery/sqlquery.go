@@ -0,0 +1,58 @@
+// Package sqlquery builds the WHERE, ORDER BY and LIMIT parts of list queries.
+// The SQL it writes is understood by both MySQL and SQLite.
+package sqlquery
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Where collects conditions joined by AND, with their arguments.
+type Where struct {
+	conds []string
+	args  []any
+}
+
+func (w *Where) Add(cond string, args ...any) {
+	w.conds = append(w.conds, cond)
+	w.args = append(w.args, args...)
+}
+
+// String is " WHERE ..." or "" when there are no conditions.
+func (w *Where) String() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+func (w *Where) Args() []any {
+	return w.args
+}
+
+// Contains returns the pattern for "column LIKE ? ESCAPE '!'" that matches s anywhere.
+func Contains(s string) string {
+	s = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+	return "%" + s + "%"
+}
+
+// Page returns " ORDER BY ... LIMIT ? OFFSET ?" and its arguments. The sort
+// field must be one of sortFields, which name the table columns; id breaks ties
+// so that pages never overlap.
+func Page(p entities.PageRequest, sortFields []string) (string, []any, error) {
+	if !slices.Contains(sortFields, p.Sort) {
+		return "", nil, fmt.Errorf("cannot sort by %q", p.Sort)
+	}
+	direction := "ASC"
+	if p.Direction == entities.SortDesc {
+		direction = "DESC"
+	}
+	clause := " ORDER BY " + p.Sort + " " + direction
+	if p.Sort != "id" {
+		clause += ", id " + direction
+	}
+	return clause + " LIMIT ? OFFSET ?", []any{p.Limit, p.Offset}, nil
+}
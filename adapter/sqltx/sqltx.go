@@ -0,0 +1,64 @@
+// Package sqltx carries a database transaction through a context, so that
+// every repository built on the same *sql.DB can take part in it.
+package sqltx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Querier is what the repositories query through: the part of *sql.DB and
+// *sql.Tx they use, with every statement traced.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *Row
+}
+
+// conn is the part of *sql.DB and *sql.Tx a Querier wraps.
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{ db *sql.DB }
+
+// From returns the transaction on db that ctx carries, or db itself.
+func From(ctx context.Context, db *sql.DB) Querier {
+	if tx, ok := ctx.Value(txKey{db}).(*sql.Tx); ok {
+		return tracedConn{tx}
+	}
+	return tracedConn{db}
+}
+
+// Do runs fn in a transaction on db and commits it if fn returns nil. When ctx
+// already carries a transaction on db, fn runs in that one instead.
+func Do(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{db}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{db}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UnitOfWork is the unit of work port over one database.
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return Do(ctx, u.db, fn)
+}
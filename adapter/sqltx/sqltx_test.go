@@ -0,0 +1,22 @@
+package sqltx_test
+
+import (
+	"testing"
+
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/port/contract"
+	"github.com/wittawat/go-hex/db/dbtest"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			contract.RunUnitOfWork(t, func(t *testing.T) contract.UnitOfWorkStores {
+				db := dialect.Open(t)
+				products := productAdapter.NewSqlProductRepository(db)
+				return contract.UnitOfWorkStores{Uow: sqltx.NewUnitOfWork(db), Products: products, Inventory: products}
+			})
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package sqltx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer comes from the global provider, which does nothing until main
+// installs one.
+var tracer = otel.Tracer("github.com/wittawat/go-hex/adapter/sqltx")
+
+// Span attributes. Only the statement is recorded, never its arguments, which
+// may hold password hashes or other personal data.
+const (
+	attrStatement    = attribute.Key("db.statement")
+	attrRowsAffected = attribute.Key("db.rows_affected")
+	attrRowsReturned = attribute.Key("db.rows_returned")
+)
+
+// tracedConn gives every statement run on conn a client span.
+type tracedConn struct {
+	conn conn
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startSpan(ctx, query)
+	defer span.End()
+	result, err := c.conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		recordError(span, err)
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(attrRowsAffected.Int64(n))
+	}
+	return result, nil
+}
+
+func (c tracedConn) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	ctx, span := startSpan(ctx, query)
+	rows, err := c.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		recordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &Rows{Rows: rows, span: span}, nil
+}
+
+func (c tracedConn) QueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	ctx, span := startSpan(ctx, query)
+	return &Row{row: c.conn.QueryRowContext(ctx, query, args...), span: span}
+}
+
+// Rows is *sql.Rows whose span ends, with the number of rows read, when the
+// rows are closed.
+type Rows struct {
+	*sql.Rows
+	span  trace.Span
+	count int
+	ended bool
+}
+
+func (r *Rows) Next() bool {
+	if !r.Rows.Next() {
+		return false
+	}
+	r.count++
+	return true
+}
+
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	if !r.ended {
+		r.ended = true
+		if err := r.Rows.Err(); err != nil {
+			recordError(r.span, err)
+		}
+		r.span.SetAttributes(attrRowsReturned.Int(r.count))
+		r.span.End()
+	}
+	return err
+}
+
+// Row is *sql.Row whose span ends when it is scanned.
+type Row struct {
+	row  *sql.Row
+	span trace.Span
+}
+
+func (r *Row) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	switch {
+	case err == nil:
+		r.span.SetAttributes(attrRowsReturned.Int(1))
+	case errors.Is(err, sql.ErrNoRows):
+		r.span.SetAttributes(attrRowsReturned.Int(0))
+	default:
+		recordError(r.span, err)
+	}
+	r.span.End()
+	return err
+}
+
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, operation(query), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrStatement.String(query)))
+}
+
+// operation names a span after the statement's first keyword, e.g. "SELECT".
+func operation(query string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return strings.ToUpper(keyword)
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,35 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/auth"
+)
+
+type authInbound struct {
+	next port.AuthInbound
+}
+
+// NewAuthInbound starts a span named "AuthInbound.<method>" around every call to next.
+func NewAuthInbound(next port.AuthInbound) port.AuthInbound {
+	return &authInbound{next: next}
+}
+
+func (s *authInbound) Login(ctx context.Context, email, password string) (_ *entities.TokenPair, err error) {
+	ctx, span := start(ctx, "AuthInbound.Login")
+	defer end(span, &err)
+	return s.next.Login(ctx, email, password)
+}
+
+func (s *authInbound) Refresh(ctx context.Context, refreshToken string) (_ *entities.TokenPair, err error) {
+	ctx, span := start(ctx, "AuthInbound.Refresh")
+	defer end(span, &err)
+	return s.next.Refresh(ctx, refreshToken)
+}
+
+func (s *authInbound) Authenticate(ctx context.Context, accessToken string) (_ *entities.Identity, err error) {
+	ctx, span := start(ctx, "AuthInbound.Authenticate")
+	defer end(span, &err)
+	return s.next.Authenticate(ctx, accessToken)
+}
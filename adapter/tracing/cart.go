@@ -0,0 +1,86 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/cart"
+)
+
+type cartService struct {
+	next port.CartService
+}
+
+// NewCartService starts a span named "CartService.<method>" around every call to next.
+func NewCartService(next port.CartService) port.CartService {
+	return &cartService{next: next}
+}
+
+func (s *cartService) Get(ctx context.Context) (_ *entities.Cart, err error) {
+	ctx, span := start(ctx, "CartService.Get")
+	defer end(span, &err)
+	return s.next.Get(ctx)
+}
+
+func (s *cartService) AddItem(ctx context.Context, productId int, quantity uint) (_ *entities.Cart, err error) {
+	ctx, span := start(ctx, "CartService.AddItem")
+	defer end(span, &err)
+	return s.next.AddItem(ctx, productId, quantity)
+}
+
+func (s *cartService) UpdateItem(ctx context.Context, productId int, quantity uint) (_ *entities.Cart, err error) {
+	ctx, span := start(ctx, "CartService.UpdateItem")
+	defer end(span, &err)
+	return s.next.UpdateItem(ctx, productId, quantity)
+}
+
+func (s *cartService) RemoveItem(ctx context.Context, productId int) (_ *entities.Cart, err error) {
+	ctx, span := start(ctx, "CartService.RemoveItem")
+	defer end(span, &err)
+	return s.next.RemoveItem(ctx, productId)
+}
+
+func (s *cartService) Clear(ctx context.Context) (err error) {
+	ctx, span := start(ctx, "CartService.Clear")
+	defer end(span, &err)
+	return s.next.Clear(ctx)
+}
+
+func (s *cartService) Checkout(ctx context.Context) (_ *entities.Order, err error) {
+	ctx, span := start(ctx, "CartService.Checkout")
+	defer end(span, &err)
+	return s.next.Checkout(ctx)
+}
+
+type cartRepository struct {
+	next port.CartRepository
+}
+
+// NewCartRepository starts a span named "CartRepository.<method>" around every call to next.
+func NewCartRepository(next port.CartRepository) port.CartRepository {
+	return &cartRepository{next: next}
+}
+
+func (r *cartRepository) FindByUserId(ctx context.Context, userId int) (_ []entities.CartItem, err error) {
+	ctx, span := start(ctx, "CartRepository.FindByUserId")
+	defer end(span, &err)
+	return r.next.FindByUserId(ctx, userId)
+}
+
+func (r *cartRepository) SetItem(ctx context.Context, userId int, productId int, quantity uint) (err error) {
+	ctx, span := start(ctx, "CartRepository.SetItem")
+	defer end(span, &err)
+	return r.next.SetItem(ctx, userId, productId, quantity)
+}
+
+func (r *cartRepository) RemoveItem(ctx context.Context, userId int, productId int) (err error) {
+	ctx, span := start(ctx, "CartRepository.RemoveItem")
+	defer end(span, &err)
+	return r.next.RemoveItem(ctx, userId, productId)
+}
+
+func (r *cartRepository) Clear(ctx context.Context, userId int) (err error) {
+	ctx, span := start(ctx, "CartRepository.Clear")
+	defer end(span, &err)
+	return r.next.Clear(ctx, userId)
+}
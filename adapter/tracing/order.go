@@ -0,0 +1,104 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/order"
+)
+
+type orderService struct {
+	next port.OrderService
+}
+
+// NewOrderService starts a span named "OrderService.<method>" around every call to next.
+func NewOrderService(next port.OrderService) port.OrderService {
+	return &orderService{next: next}
+}
+
+func (s *orderService) Create(ctx context.Context, order *entities.Order) (_ int, err error) {
+	ctx, span := start(ctx, "OrderService.Create")
+	defer end(span, &err)
+	return s.next.Create(ctx, order)
+}
+
+func (s *orderService) GetById(ctx context.Context, id int) (_ *entities.Order, err error) {
+	ctx, span := start(ctx, "OrderService.GetById")
+	defer end(span, &err)
+	return s.next.GetById(ctx, id)
+}
+
+func (s *orderService) GetByUser(ctx context.Context, userId int) (_ []entities.Order, err error) {
+	ctx, span := start(ctx, "OrderService.GetByUser")
+	defer end(span, &err)
+	return s.next.GetByUser(ctx, userId)
+}
+
+func (s *orderService) UpdateStatus(ctx context.Context, id int, status entities.OrderStatus) (_ *entities.Order, err error) {
+	ctx, span := start(ctx, "OrderService.UpdateStatus")
+	defer end(span, &err)
+	return s.next.UpdateStatus(ctx, id, status)
+}
+
+func (s *orderService) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := start(ctx, "OrderService.Delete")
+	defer end(span, &err)
+	return s.next.Delete(ctx, id)
+}
+
+func (s *orderService) Pay(ctx context.Context, id int) (_ *entities.Payment, err error) {
+	ctx, span := start(ctx, "OrderService.Pay")
+	defer end(span, &err)
+	return s.next.Pay(ctx, id)
+}
+
+func (s *orderService) GetPayments(ctx context.Context, id int) (_ []entities.Payment, err error) {
+	ctx, span := start(ctx, "OrderService.GetPayments")
+	defer end(span, &err)
+	return s.next.GetPayments(ctx, id)
+}
+
+func (s *orderService) SettlePayment(ctx context.Context, result entities.PaymentResult) (_ *entities.Payment, err error) {
+	ctx, span := start(ctx, "OrderService.SettlePayment")
+	defer end(span, &err)
+	return s.next.SettlePayment(ctx, result)
+}
+
+type orderRepository struct {
+	next port.OrderRepository
+}
+
+// NewOrderRepository starts a span named "OrderRepository.<method>" around every call to next.
+func NewOrderRepository(next port.OrderRepository) port.OrderRepository {
+	return &orderRepository{next: next}
+}
+
+func (r *orderRepository) Save(ctx context.Context, order *entities.Order) (_ int, err error) {
+	ctx, span := start(ctx, "OrderRepository.Save")
+	defer end(span, &err)
+	return r.next.Save(ctx, order)
+}
+
+func (r *orderRepository) FindById(ctx context.Context, id int) (_ *entities.Order, err error) {
+	ctx, span := start(ctx, "OrderRepository.FindById")
+	defer end(span, &err)
+	return r.next.FindById(ctx, id)
+}
+
+func (r *orderRepository) FindByUserId(ctx context.Context, userId int) (_ []entities.Order, err error) {
+	ctx, span := start(ctx, "OrderRepository.FindByUserId")
+	defer end(span, &err)
+	return r.next.FindByUserId(ctx, userId)
+}
+
+func (r *orderRepository) UpdateStatus(ctx context.Context, id int, from, to entities.OrderStatus) (err error) {
+	ctx, span := start(ctx, "OrderRepository.UpdateStatus")
+	defer end(span, &err)
+	return r.next.UpdateStatus(ctx, id, from, to)
+}
+
+func (r *orderRepository) DeleteOne(ctx context.Context, id int) (err error) {
+	ctx, span := start(ctx, "OrderRepository.DeleteOne")
+	defer end(span, &err)
+	return r.next.DeleteOne(ctx, id)
+}
@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/payment"
+)
+
+type paymentRepository struct {
+	next port.PaymentRepository
+}
+
+// NewPaymentRepository starts a span named "PaymentRepository.<method>" around every call to next.
+func NewPaymentRepository(next port.PaymentRepository) port.PaymentRepository {
+	return &paymentRepository{next: next}
+}
+
+func (r *paymentRepository) Save(ctx context.Context, payment *entities.Payment) (_ int, err error) {
+	ctx, span := start(ctx, "PaymentRepository.Save")
+	defer end(span, &err)
+	return r.next.Save(ctx, payment)
+}
+
+func (r *paymentRepository) FindByReference(ctx context.Context, reference string) (_ *entities.Payment, err error) {
+	ctx, span := start(ctx, "PaymentRepository.FindByReference")
+	defer end(span, &err)
+	return r.next.FindByReference(ctx, reference)
+}
+
+func (r *paymentRepository) FindByOrderId(ctx context.Context, orderId int) (_ []entities.Payment, err error) {
+	ctx, span := start(ctx, "PaymentRepository.FindByOrderId")
+	defer end(span, &err)
+	return r.next.FindByOrderId(ctx, orderId)
+}
+
+func (r *paymentRepository) Settle(ctx context.Context, id int, result entities.PaymentResult) (err error) {
+	ctx, span := start(ctx, "PaymentRepository.Settle")
+	defer end(span, &err)
+	return r.next.Settle(ctx, id, result)
+}
+
+func (r *paymentRepository) MarkRefundDue(ctx context.Context, id int) (err error) {
+	ctx, span := start(ctx, "PaymentRepository.MarkRefundDue")
+	defer end(span, &err)
+	return r.next.MarkRefundDue(ctx, id)
+}
+
+type paymentGateway struct {
+	next port.PaymentGateway
+}
+
+// NewPaymentGateway starts a span named "PaymentGateway.<method>" around every call to next.
+func NewPaymentGateway(next port.PaymentGateway) port.PaymentGateway {
+	return &paymentGateway{next: next}
+}
+
+func (g *paymentGateway) Authorize(ctx context.Context, payment entities.Payment) (_ entities.PaymentResult, err error) {
+	ctx, span := start(ctx, "PaymentGateway.Authorize")
+	defer end(span, &err)
+	return g.next.Authorize(ctx, payment)
+}
@@ -0,0 +1,119 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/product"
+)
+
+type productInbound struct {
+	next port.ProductInbound
+}
+
+// NewProductInbound starts a span named "ProductInbound.<method>" around every call to next.
+func NewProductInbound(next port.ProductInbound) port.ProductInbound {
+	return &productInbound{next: next}
+}
+
+func (s *productInbound) Save(ctx context.Context, product *entities.Product) (_ int, err error) {
+	ctx, span := start(ctx, "ProductInbound.Save")
+	defer end(span, &err)
+	return s.next.Save(ctx, product)
+}
+
+func (s *productInbound) FindById(ctx context.Context, id int) (_ *entities.Product, err error) {
+	ctx, span := start(ctx, "ProductInbound.FindById")
+	defer end(span, &err)
+	return s.next.FindById(ctx, id)
+}
+
+func (s *productInbound) Find(ctx context.Context, query entities.ProductQuery) (_ []entities.Product, _ entities.PageInfo, err error) {
+	ctx, span := start(ctx, "ProductInbound.Find")
+	defer end(span, &err)
+	return s.next.Find(ctx, query)
+}
+
+func (s *productInbound) UpdateOne(ctx context.Context, product *entities.Product, id int) (err error) {
+	ctx, span := start(ctx, "ProductInbound.UpdateOne")
+	defer end(span, &err)
+	return s.next.UpdateOne(ctx, product, id)
+}
+
+func (s *productInbound) DeleteOne(ctx context.Context, id int) (err error) {
+	ctx, span := start(ctx, "ProductInbound.DeleteOne")
+	defer end(span, &err)
+	return s.next.DeleteOne(ctx, id)
+}
+
+func (s *productInbound) SetStock(ctx context.Context, id int, stock uint) (err error) {
+	ctx, span := start(ctx, "ProductInbound.SetStock")
+	defer end(span, &err)
+	return s.next.SetStock(ctx, id, stock)
+}
+
+type productOutbound struct {
+	next port.ProductOutbound
+}
+
+// NewProductOutbound starts a span named "ProductOutbound.<method>" around every call to next.
+func NewProductOutbound(next port.ProductOutbound) port.ProductOutbound {
+	return &productOutbound{next: next}
+}
+
+func (r *productOutbound) Save(ctx context.Context, product *entities.Product) (_ int, err error) {
+	ctx, span := start(ctx, "ProductOutbound.Save")
+	defer end(span, &err)
+	return r.next.Save(ctx, product)
+}
+
+func (r *productOutbound) FindById(ctx context.Context, id int) (_ *entities.Product, err error) {
+	ctx, span := start(ctx, "ProductOutbound.FindById")
+	defer end(span, &err)
+	return r.next.FindById(ctx, id)
+}
+
+func (r *productOutbound) Find(ctx context.Context, query entities.ProductQuery) (_ []entities.Product, _ entities.PageInfo, err error) {
+	ctx, span := start(ctx, "ProductOutbound.Find")
+	defer end(span, &err)
+	return r.next.Find(ctx, query)
+}
+
+func (r *productOutbound) UpdateOne(ctx context.Context, product *entities.Product, id int) (err error) {
+	ctx, span := start(ctx, "ProductOutbound.UpdateOne")
+	defer end(span, &err)
+	return r.next.UpdateOne(ctx, product, id)
+}
+
+func (r *productOutbound) DeleteOne(ctx context.Context, id int) (err error) {
+	ctx, span := start(ctx, "ProductOutbound.DeleteOne")
+	defer end(span, &err)
+	return r.next.DeleteOne(ctx, id)
+}
+
+type inventoryOutbound struct {
+	next port.InventoryOutbound
+}
+
+// NewInventoryOutbound starts a span named "InventoryOutbound.<method>" around every call to next.
+func NewInventoryOutbound(next port.InventoryOutbound) port.InventoryOutbound {
+	return &inventoryOutbound{next: next}
+}
+
+func (r *inventoryOutbound) Reserve(ctx context.Context, items []entities.OrderItem) (err error) {
+	ctx, span := start(ctx, "InventoryOutbound.Reserve")
+	defer end(span, &err)
+	return r.next.Reserve(ctx, items)
+}
+
+func (r *inventoryOutbound) Release(ctx context.Context, items []entities.OrderItem) (err error) {
+	ctx, span := start(ctx, "InventoryOutbound.Release")
+	defer end(span, &err)
+	return r.next.Release(ctx, items)
+}
+
+func (r *inventoryOutbound) SetStock(ctx context.Context, productId int, stock uint) (err error) {
+	ctx, span := start(ctx, "InventoryOutbound.SetStock")
+	defer end(span, &err)
+	return r.next.SetStock(ctx, productId, stock)
+}
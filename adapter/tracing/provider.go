@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/wittawat/go-hex/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Install makes the exporter named by cfg the global tracer provider, and W3C
+// trace context the global propagator. The returned shutdown flushes the spans
+// still buffered; call it once the server has stopped.
+func Install(ctx context.Context, cfg config.TracingConfig) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if cfg.Exporter == config.TracingNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closeOutput())
+	}, nil
+}
+
+// newExporter also returns how to close what the exporter writes to.
+func newExporter(ctx context.Context, cfg config.TracingConfig) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+	switch cfg.Exporter {
+	case config.TracingOTLP:
+		endpoint, err := url.JoinPath(cfg.Endpoint, "v1/traces")
+		if err != nil {
+			return nil, nil, fmt.Errorf("tracing endpoint: %w", err)
+		}
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+		return exporter, noClose, err
+	case config.TracingStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, noClose, err
+	case config.TracingFile:
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file.Close, nil
+	}
+	return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+}
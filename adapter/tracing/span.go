@@ -0,0 +1,35 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wittawat/go-hex/core/entities"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/wittawat/go-hex/adapter/tracing")
+
+// start opens a child span of the one ctx carries.
+func start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name)
+}
+
+// end closes span after the call failed with *err, if not nil. Domain errors
+// such as not_found are expected answers, so they are only labelled; anything
+// else marks the span failed. It is meant to be deferred.
+func end(span trace.Span, err *error) {
+	var domainErr *entities.Error
+	switch {
+	case *err == nil:
+	case errors.As(*err, &domainErr):
+		span.SetAttributes(attribute.String("error.type", string(domainErr.Kind)))
+	default:
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,104 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/user"
+)
+
+type userInbound struct {
+	next port.UserInbound
+}
+
+// NewUserInbound starts a span named "UserInbound.<method>" around every call to next.
+func NewUserInbound(next port.UserInbound) port.UserInbound {
+	return &userInbound{next: next}
+}
+
+func (s *userInbound) Save(ctx context.Context, user *entities.User) (_ int, err error) {
+	ctx, span := start(ctx, "UserInbound.Save")
+	defer end(span, &err)
+	return s.next.Save(ctx, user)
+}
+
+func (s *userInbound) FindById(ctx context.Context, id int) (_ *entities.User, err error) {
+	ctx, span := start(ctx, "UserInbound.FindById")
+	defer end(span, &err)
+	return s.next.FindById(ctx, id)
+}
+
+func (s *userInbound) Find(ctx context.Context, query entities.UserQuery) (_ []entities.User, _ entities.PageInfo, err error) {
+	ctx, span := start(ctx, "UserInbound.Find")
+	defer end(span, &err)
+	return s.next.Find(ctx, query)
+}
+
+func (s *userInbound) UpdateOne(ctx context.Context, user *entities.User, id int) (err error) {
+	ctx, span := start(ctx, "UserInbound.UpdateOne")
+	defer end(span, &err)
+	return s.next.UpdateOne(ctx, user, id)
+}
+
+func (s *userInbound) DeleteOne(ctx context.Context, id int) (err error) {
+	ctx, span := start(ctx, "UserInbound.DeleteOne")
+	defer end(span, &err)
+	return s.next.DeleteOne(ctx, id)
+}
+
+func (s *userInbound) VerifyCredentials(ctx context.Context, email, password string) (_ *entities.User, err error) {
+	ctx, span := start(ctx, "UserInbound.VerifyCredentials")
+	defer end(span, &err)
+	return s.next.VerifyCredentials(ctx, email, password)
+}
+
+func (s *userInbound) EnsureAdmin(ctx context.Context, username, email, password string) (err error) {
+	ctx, span := start(ctx, "UserInbound.EnsureAdmin")
+	defer end(span, &err)
+	return s.next.EnsureAdmin(ctx, username, email, password)
+}
+
+type userOutbound struct {
+	next port.UserOutbound
+}
+
+// NewUserOutbound starts a span named "UserOutbound.<method>" around every call to next.
+func NewUserOutbound(next port.UserOutbound) port.UserOutbound {
+	return &userOutbound{next: next}
+}
+
+func (r *userOutbound) Save(ctx context.Context, user *entities.User) (_ int, err error) {
+	ctx, span := start(ctx, "UserOutbound.Save")
+	defer end(span, &err)
+	return r.next.Save(ctx, user)
+}
+
+func (r *userOutbound) FindById(ctx context.Context, id int) (_ *entities.User, err error) {
+	ctx, span := start(ctx, "UserOutbound.FindById")
+	defer end(span, &err)
+	return r.next.FindById(ctx, id)
+}
+
+func (r *userOutbound) FindByEmail(ctx context.Context, email string) (_ *entities.User, err error) {
+	ctx, span := start(ctx, "UserOutbound.FindByEmail")
+	defer end(span, &err)
+	return r.next.FindByEmail(ctx, email)
+}
+
+func (r *userOutbound) Find(ctx context.Context, query entities.UserQuery) (_ []entities.User, _ entities.PageInfo, err error) {
+	ctx, span := start(ctx, "UserOutbound.Find")
+	defer end(span, &err)
+	return r.next.Find(ctx, query)
+}
+
+func (r *userOutbound) UpdateOne(ctx context.Context, user *entities.User, id int) (err error) {
+	ctx, span := start(ctx, "UserOutbound.UpdateOne")
+	defer end(span, &err)
+	return r.next.UpdateOne(ctx, user, id)
+}
+
+func (r *userOutbound) DeleteOne(ctx context.Context, id int) (err error) {
+	ctx, span := start(ctx, "UserOutbound.DeleteOne")
+	defer end(span, &err)
+	return r.next.DeleteOne(ctx, id)
+}
@@ -0,0 +1,38 @@
+package adapter
+
+import (
+	"context"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type BcryptPasswordHasher struct {
+	cost int
+}
+
+// NewBcryptPasswordHasher falls back to bcrypt.DefaultCost when cost is out of range.
+func NewBcryptPasswordHasher(cost int) *BcryptPasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptPasswordHasher{cost: cost}
+}
+
+// Hash and Compare cannot be interrupted once started, so they only check ctx up front.
+func (h *BcryptPasswordHasher) Hash(ctx context.Context, password string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptPasswordHasher) Compare(ctx context.Context, hash, password string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
@@ -0,0 +1,34 @@
+package adapter_test
+
+import (
+	"strings"
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/user"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcryptPasswordHasher(t *testing.T) {
+	hasher := adapter.NewBcryptPasswordHasher(bcrypt.MinCost)
+	hash, err := hasher.Hash(t.Context(), "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(hash, "correct horse") {
+		t.Fatalf("hash %q carries the password", hash)
+	}
+	if err := hasher.Compare(t.Context(), hash, "correct horse"); err != nil {
+		t.Errorf("Compare with the hashed password: %v", err)
+	}
+	if err := hasher.Compare(t.Context(), hash, "battery staple"); err == nil {
+		t.Error("Compare with another password succeeded")
+	}
+
+	again, err := hasher.Hash(t.Context(), "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == hash {
+		t.Error("hashing a password twice gave the same hash, want a fresh salt")
+	}
+}
@@ -1,11 +1,12 @@
 package adapter
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/wittawat/go-hex/core/entities"
+	"github.com/wittawat/go-hex/adapter/httpx"
 	port "github.com/wittawat/go-hex/core/port/user"
 )
 
@@ -18,59 +19,65 @@ func NewHttpUserHandler(ib port.UserInbound) *HttpUserHandler {
 }
 
 func (h *HttpUserHandler) Register(c *gin.Context) {
-	var user entities.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+	var req CreateUserRequest
+	if !httpx.BindJSON(c, &req) {
 		return
 	}
+	user := req.User()
 
-	if err := h.ib.Save(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	id, err := h.ib.Save(c.Request.Context(), &user)
+	if err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Created user successfully"})
+	c.Header("Location", fmt.Sprintf("/users/%d", id))
+	httpx.Respond(c, http.StatusCreated, "Created user successfully", NewUserResponse(&user))
 }
 
 func (h *HttpUserHandler) GetUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		httpx.BadRequest(c, "Invalid input")
 		return
 	}
-	user, err := h.ib.FindById(id)
+	user, err := h.ib.FindById(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Get user successfully", "user": user})
+	httpx.Respond(c, http.StatusOK, "Get user successfully", NewUserResponse(user))
 }
 
 func (h *HttpUserHandler) GetAllUser(c *gin.Context) {
-	users, err := h.ib.Find()
+	var req ListUsersRequest
+	if !httpx.BindQuery(c, &req) {
+		return
+	}
+	users, page, err := h.ib.Find(c.Request.Context(), req.Query())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid input"})
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Get all user successfully", "users": users})
+	httpx.RespondPage(c, "Get users successfully", NewUserResponses(users), page)
 }
 
 func (h *HttpUserHandler) UpdateUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		httpx.BadRequest(c, "Invalid user id")
 		return
 	}
 
-	var user entities.User
-	if err = c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON input"})
+	var req UpdateUserRequest
+	if !httpx.BindJSON(c, &req) {
 		return
 	}
+	user := req.User()
 
-	existUser, err := h.ib.FindById(id)
+	existUser, err := h.ib.FindById(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		httpx.AbortWithError(c, err)
 		return
 	}
 
@@ -80,26 +87,23 @@ func (h *HttpUserHandler) UpdateUser(c *gin.Context) {
 	if user.Email == "" {
 		user.Email = existUser.Email
 	}
-	if user.Password == "" {
-		user.Password = existUser.Password
-	}
 
-	if err = h.ib.UpdateOne(&user, id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err = h.ib.UpdateOne(c.Request.Context(), &user, id); err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Updated user successfully"})
+	httpx.Respond(c, http.StatusOK, "Updated user successfully", nil)
 }
 
 func (h *HttpUserHandler) DeleteUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		httpx.BadRequest(c, "Invalid user id")
 		return
 	}
-	if err = h.ib.DeleteOne(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err = h.ib.DeleteOne(c.Request.Context(), id); err != nil {
+		httpx.AbortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Deleted user successfully"})
+	httpx.Respond(c, http.StatusOK, "Deleted user successfully", nil)
 }
@@ -0,0 +1,76 @@
+package adapter
+
+import (
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// UserResponse is the public shape of a user; it never carries the password hash.
+type UserResponse struct {
+	Id        int           `json:"id"`
+	Username  string        `json:"username"`
+	Email     string        `json:"email"`
+	Role      entities.Role `json:"role"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func NewUserResponse(user *entities.User) UserResponse {
+	return UserResponse{
+		Id:        user.Id,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func NewUserResponses(users []entities.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, NewUserResponse(&users[i]))
+	}
+	return responses
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,max=64"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=4,max=72"`
+}
+
+func (r *CreateUserRequest) User() entities.User {
+	return entities.User{Username: r.Username, Email: r.Email, Password: r.Password}
+}
+
+// UpdateUserRequest is a partial update; empty fields keep their stored value.
+type UpdateUserRequest struct {
+	Username string        `json:"username" binding:"omitempty,max=64"`
+	Email    string        `json:"email" binding:"omitempty,email,max=255"`
+	Password string        `json:"password" binding:"omitempty,min=4,max=72"`
+	Role     entities.Role `json:"role" binding:"omitempty,oneof=admin staff customer"`
+}
+
+func (r *UpdateUserRequest) User() entities.User {
+	return entities.User{Username: r.Username, Email: r.Email, Password: r.Password, Role: r.Role}
+}
+
+// ListUsersRequest holds the query parameters of GET /users.
+type ListUsersRequest struct {
+	Limit  int           `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string        `form:"cursor"`
+	Sort   string        `form:"sort" binding:"omitempty,oneof=id username email created_at"`
+	Order  string        `form:"order" binding:"omitempty,oneof=asc desc"`
+	Role   entities.Role `form:"role" binding:"omitempty,oneof=admin staff customer"`
+	Search string        `form:"q"`
+}
+
+func (r *ListUsersRequest) Query() entities.UserQuery {
+	return entities.UserQuery{
+		PageRequest: entities.PageRequest{Limit: r.Limit, Cursor: r.Cursor, Sort: r.Sort, Direction: entities.SortDirection(r.Order)},
+		Role:        r.Role,
+		Search:      r.Search,
+	}
+}
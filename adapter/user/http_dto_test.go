@@ -0,0 +1,28 @@
+package adapter_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+func TestUserResponseLeavesOutPassword(t *testing.T) {
+	const hash = "$2a$10$abcdefghijklmnopqrstuuNOTAREALHASHATALLxxxxxxxxxxxxxx"
+	users := []entities.User{{Id: 1, Username: "alice", Email: "alice@example.com", Password: hash, Role: entities.RoleCustomer}}
+
+	for name, response := range map[string]any{
+		"NewUserResponse":  adapter.NewUserResponse(&users[0]),
+		"NewUserResponses": adapter.NewUserResponses(users),
+	} {
+		body, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(body), hash) || strings.Contains(string(body), "password") {
+			t.Errorf("%s encodes as %s, want no password", name, body)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package adapter
+
+import (
+	"cmp"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/memquery"
+	"github.com/wittawat/go-hex/adapter/memtx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// secondary port backed by a map, for running without a database
+type MemoryUserRepository struct {
+	mu     sync.RWMutex
+	nextId int
+	users  map[int]entities.User
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{users: make(map[int]entities.User)}
+}
+
+func (r *MemoryUserRepository) Save(ctx context.Context, user *entities.User) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.emailTaken(user.Email, 0) {
+		return 0, entities.Conflict("email %s already exists", user.Email)
+	}
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	r.nextId++
+	user.Id, user.CreatedAt, user.UpdatedAt = r.nextId, now, now
+	r.users[user.Id] = *user
+	id := user.Id
+	memtx.OnRollbackLocked(ctx, &r.mu, func() { delete(r.users, id) })
+	return user.Id, nil
+}
+
+func (r *MemoryUserRepository) Find(ctx context.Context, query entities.UserQuery) ([]entities.User, entities.PageInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var users []entities.User
+	for _, user := range r.users {
+		if query.Role != "" && user.Role != query.Role {
+			continue
+		}
+		if query.Search != "" && !memquery.Contains(user.Username, query.Search) && !memquery.Contains(user.Email, query.Search) {
+			continue
+		}
+		users = append(users, user)
+	}
+	users, page := memquery.Page(users, query.PageRequest, compareUsers(query.Sort))
+	return users, page, nil
+}
+
+func (r *MemoryUserRepository) FindById(ctx context.Context, id int) (*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, entities.NotFound("user %d not found", id)
+	}
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, entities.NotFound("user %s not found", email)
+}
+
+func (r *MemoryUserRepository) UpdateOne(ctx context.Context, user *entities.User, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exist, ok := r.users[id]
+	if !ok {
+		return entities.NotFound("user %d not found", id)
+	}
+	if r.emailTaken(user.Email, id) {
+		return entities.Conflict("email %s already exists", user.Email)
+	}
+	user.Id, user.CreatedAt, user.UpdatedAt = id, exist.CreatedAt, time.Now().UTC().Truncate(time.Microsecond)
+	r.users[id] = *user
+	written := *user
+	// puts the user back unless someone changed it since
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		if current, ok := r.users[id]; ok && current == written {
+			r.users[id] = exist
+		}
+	})
+	return nil
+}
+
+func (r *MemoryUserRepository) DeleteOne(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exist, ok := r.users[id]
+	if !ok {
+		return entities.NotFound("user %d not found", id)
+	}
+	delete(r.users, id)
+	memtx.OnRollbackLocked(ctx, &r.mu, func() {
+		if _, ok := r.users[id]; !ok && !r.emailTaken(exist.Email, id) {
+			r.users[id] = exist
+		}
+	})
+	return nil
+}
+
+// emailTaken mirrors the unique index on users.email.
+func (r *MemoryUserRepository) emailTaken(email string, exceptId int) bool {
+	for id, user := range r.users {
+		if id != exceptId && user.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+// compareUsers orders users by one of entities.UserSortFields, then by id.
+func compareUsers(field string) func(a, b entities.User) int {
+	return func(a, b entities.User) int {
+		var c int
+		switch field {
+		case "username":
+			c = strings.Compare(a.Username, b.Username)
+		case "email":
+			c = strings.Compare(a.Email, b.Email)
+		case "created_at":
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c == 0 {
+			c = cmp.Compare(a.Id, b.Id)
+		}
+		return c
+	}
+}
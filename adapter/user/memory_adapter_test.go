@@ -0,0 +1,15 @@
+package adapter_test
+
+import (
+	"testing"
+
+	adapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/port/contract"
+	port "github.com/wittawat/go-hex/core/port/user"
+)
+
+func TestMemoryUserRepository(t *testing.T) {
+	contract.RunUserOutbound(t, func(t *testing.T) port.UserOutbound {
+		return adapter.NewMemoryUserRepository()
+	})
+}
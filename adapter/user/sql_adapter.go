@@ -0,0 +1,105 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/sqlerr"
+	"github.com/wittawat/go-hex/adapter/sqlquery"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// secondary port
+type SqlUserRepository struct {
+	db *sql.DB
+}
+
+func NewSqlUserRepository(db *sql.DB) *SqlUserRepository {
+	return &SqlUserRepository{db: db}
+}
+
+func (r *SqlUserRepository) Save(ctx context.Context, user *entities.User) (int, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	query := "INSERT INTO users (username, email, password, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, user.Username, user.Email, user.Password, user.Role, now, now)
+	if err != nil {
+		return 0, sqlerr.Translate(err, "user")
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	user.Id, user.CreatedAt, user.UpdatedAt = int(id), now, now
+	return user.Id, nil
+}
+
+func (r *SqlUserRepository) Find(ctx context.Context, query entities.UserQuery) ([]entities.User, entities.PageInfo, error) {
+	where := userFilter(query)
+	page, pageArgs, err := sqlquery.Page(query.PageRequest, entities.UserSortFields)
+	if err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+
+	var total int
+	if err := sqltx.From(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where.String(), where.Args()...).Scan(&total); err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+
+	rows, err := sqltx.From(ctx, r.db).QueryContext(ctx, "SELECT id, username, email, password, role, created_at, updated_at FROM users"+where.String()+page, slices.Concat(where.Args(), pageArgs)...)
+	if err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	defer rows.Close()
+	var users []entities.User
+	for rows.Next() {
+		var user entities.User
+		if err := rows.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, entities.PageInfo{}, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	return users, entities.NewPageInfo(query.PageRequest, len(users), total), nil
+}
+
+func (r *SqlUserRepository) FindById(ctx context.Context, id int) (*entities.User, error) {
+	var user entities.User
+	query := "SELECT id, username, email, password, role, created_at, updated_at FROM users WHERE id=?"
+	row := sqltx.From(ctx, r.db).QueryRowContext(ctx, query, id)
+	if err := row.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, sqlerr.Translate(err, "user")
+	}
+	return &user, nil
+}
+
+func (r *SqlUserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	var user entities.User
+	query := "SELECT id, username, email, password, role, created_at, updated_at FROM users WHERE email=?"
+	row := sqltx.From(ctx, r.db).QueryRowContext(ctx, query, email)
+	if err := row.Scan(&user.Id, &user.Username, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, sqlerr.Translate(err, "user")
+	}
+	return &user, nil
+}
+
+func (r *SqlUserRepository) UpdateOne(ctx context.Context, user *entities.User, id int) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	query := "UPDATE users SET username=?, email=?, password=?, role=?, updated_at=? WHERE id=?"
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, user.Username, user.Email, user.Password, user.Role, now, id)
+	if err := sqlerr.Affected(result, err, "user"); err != nil {
+		return err
+	}
+	user.Id, user.UpdatedAt = id, now
+	return nil
+}
+
+func (r *SqlUserRepository) DeleteOne(ctx context.Context, id int) error {
+	query := "DELETE FROM users WHERE id=?"
+	result, err := sqltx.From(ctx, r.db).ExecContext(ctx, query, id)
+	return sqlerr.Affected(result, err, "user")
+}
@@ -0,0 +1,54 @@
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+
+	orderAdapter "github.com/wittawat/go-hex/adapter/order"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	adapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/entities"
+	"github.com/wittawat/go-hex/core/port/contract"
+	port "github.com/wittawat/go-hex/core/port/user"
+	"github.com/wittawat/go-hex/db/dbtest"
+)
+
+func TestSqlUserRepository(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			contract.RunUserOutbound(t, func(t *testing.T) port.UserOutbound {
+				return adapter.NewSqlUserRepository(dialect.Open(t))
+			})
+		})
+	}
+}
+
+func TestSqlUsersWithOrdersAreKept(t *testing.T) {
+	for _, dialect := range dbtest.Dialects {
+		t.Run(dialect.Name, func(t *testing.T) {
+			db := dialect.Open(t)
+			users, products, orders := adapter.NewSqlUserRepository(db), productAdapter.NewSqlProductRepository(db), orderAdapter.NewSqlOrderRepository(db)
+			price := entities.Money{Amount: 129000, Currency: entities.THB}
+			userId, err := users.Save(t.Context(), &entities.User{Username: "alice", Email: "alice@example.com", Password: "secret", Role: entities.RoleCustomer})
+			if err != nil {
+				t.Fatal(err)
+			}
+			productId, err := products.Save(t.Context(), &entities.Product{Title: "keyboard", Price: price})
+			if err != nil {
+				t.Fatal(err)
+			}
+			order := entities.Order{UserId: uint(userId), Status: entities.OrderPending, Total: price,
+				Items: []entities.OrderItem{{ProductId: uint(productId), Title: "keyboard", Quantity: 1, UnitPrice: price}}}
+			if _, err := orders.Save(t.Context(), &order); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := users.DeleteOne(t.Context(), userId); !errors.Is(err, entities.ErrConflict) {
+				t.Fatalf("DeleteOne of a user with an order = %v, want ErrConflict", err)
+			}
+			if _, err := orders.FindById(t.Context(), order.Id); err != nil {
+				t.Fatalf("order after DeleteOne of its user: %v", err)
+			}
+		})
+	}
+}
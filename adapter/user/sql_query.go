@@ -0,0 +1,19 @@
+package adapter
+
+import (
+	"github.com/wittawat/go-hex/adapter/sqlquery"
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// userFilter is the WHERE clause shared by Find and its COUNT query.
+func userFilter(query entities.UserQuery) sqlquery.Where {
+	var where sqlquery.Where
+	if query.Role != "" {
+		where.Add("role = ?", query.Role)
+	}
+	if query.Search != "" {
+		pattern := sqlquery.Contains(query.Search)
+		where.Add("(username LIKE ? ESCAPE '!' OR email LIKE ? ESCAPE '!')", pattern, pattern)
+	}
+	return where
+}
@@ -0,0 +1,491 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StorageMySQL  = "mysql"
+	StorageSQLite = "sqlite"
+	StorageMemory = "memory"
+)
+
+type Config struct {
+	Storage        string        `yaml:"storage"`
+	HTTP           HTTPConfig    `yaml:"http"`
+	MySQL          MySQLConfig   `yaml:"mysql"`
+	SQLite         SQLiteConfig  `yaml:"sqlite"`
+	Pool           PoolConfig    `yaml:"pool"`
+	Connect        ConnectConfig `yaml:"connect"`
+	Auth           AuthConfig    `yaml:"auth"`
+	Payment        PaymentConfig `yaml:"payment"`
+	Health         HealthConfig  `yaml:"health"`
+	Tracing        TracingConfig `yaml:"tracing"`
+	Log            LogConfig     `yaml:"log"`
+	MigrateOnStart bool          `yaml:"migrate_on_start"`
+}
+
+type HTTPConfig struct {
+	Host           string        `yaml:"host"`
+	Port           int           `yaml:"port"`
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout bound each
+	// connection; WriteTimeout must leave room for RequestTimeout.
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout is how long in-flight requests may take to finish after
+	// SIGINT or SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type MySQLConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+}
+
+type SQLiteConfig struct {
+	Path string `yaml:"path"`
+}
+
+type PoolConfig struct {
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+}
+
+// ConnectConfig controls how startup waits for the database to accept connections.
+type ConnectConfig struct {
+	// Attempts is how many pings are tried before giving up.
+	Attempts int `yaml:"attempts"`
+	// Backoff is the wait after the first failed ping; it doubles after every
+	// failure up to MaxBackoff.
+	Backoff     time.Duration `yaml:"backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	PingTimeout time.Duration `yaml:"ping_timeout"`
+}
+
+type AuthConfig struct {
+	BcryptCost      int           `yaml:"bcrypt_cost"`
+	JWTSecret       string        `yaml:"jwt_secret"`
+	JWTIssuer       string        `yaml:"jwt_issuer"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+	// AdminEmail and AdminPassword, when both set, make sure an admin account exists at startup.
+	AdminEmail    string `yaml:"admin_email"`
+	AdminPassword string `yaml:"admin_password"`
+}
+
+const PaymentGatewayFake = "fake"
+
+type PaymentConfig struct {
+	// Gateway names the payment gateway; only the in-process fake exists so far.
+	Gateway string `yaml:"gateway"`
+	// FakeOutcome is approve, decline or timeout.
+	FakeOutcome string        `yaml:"fake_outcome"`
+	FakeLatency time.Duration `yaml:"fake_latency"`
+	// RetryAfter is how long a payment may await the gateway's decision before
+	// paying for its order again asks the gateway about it anew.
+	RetryAfter time.Duration `yaml:"retry_after"`
+	// WebhookSecret signs gateway callbacks; when empty they are all rejected.
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+type HealthConfig struct {
+	// CheckTimeout bounds each dependency check behind /readyz.
+	CheckTimeout time.Duration `yaml:"check_timeout"`
+}
+
+const (
+	TracingNone   = "none"
+	TracingOTLP   = "otlp"
+	TracingStdout = "stdout"
+	TracingFile   = "file"
+)
+
+type TracingConfig struct {
+	// Exporter is none, otlp (OTLP over HTTP to Endpoint), stdout, or file
+	// (one JSON span per line appended to File).
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the collector's OTLP/HTTP base URL, e.g. http://localhost:4318.
+	Endpoint    string `yaml:"endpoint"`
+	File        string `yaml:"file"`
+	ServiceName string `yaml:"service_name"`
+	// SampleRatio is the share of new traces recorded; requests that arrive
+	// with a traceparent follow the caller's decision.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+const (
+	LogJSON = "json"
+	LogText = "text"
+)
+
+type LogConfig struct {
+	// Level is debug, info, warn or error.
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+func Default() Config {
+	return Config{
+		Storage: StorageMySQL,
+		HTTP: HTTPConfig{
+			Port:              3030,
+			RequestTimeout:    10 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       time.Minute,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		// there are no default credentials; MYSQL_USER and MYSQL_PASSWORD must be set
+		MySQL: MySQLConfig{
+			Host:     "127.0.0.1",
+			Port:     3306,
+			Database: "mydb",
+		},
+		SQLite: SQLiteConfig{Path: "gohex.db"},
+		Pool: PoolConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnMaxIdleTime: time.Minute,
+		},
+		Connect: ConnectConfig{
+			Attempts:    10,
+			Backoff:     500 * time.Millisecond,
+			MaxBackoff:  10 * time.Second,
+			PingTimeout: 5 * time.Second,
+		},
+		Auth: AuthConfig{
+			BcryptCost:      10,
+			JWTIssuer:       "go-hex",
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 7 * 24 * time.Hour,
+		},
+		Payment: PaymentConfig{
+			Gateway:     PaymentGatewayFake,
+			FakeOutcome: "approve",
+			RetryAfter:  10 * time.Minute,
+		},
+		Health: HealthConfig{CheckTimeout: 2 * time.Second},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			Endpoint:    "http://localhost:4318",
+			File:        "traces.jsonl",
+			ServiceName: "go-hex",
+			SampleRatio: 1,
+		},
+		Log:            LogConfig{Level: "info", Format: LogJSON},
+		MigrateOnStart: true,
+	}
+}
+
+// Load builds the configuration from the defaults, then the YAML file named by
+// CONFIG_FILE (if any), then the environment, and validates the result.
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(content, c); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	err := errors.Join(
+		lookupString("STORAGE", &c.Storage),
+		lookupString("HTTP_HOST", &c.HTTP.Host),
+		lookupInt("HTTP_PORT", &c.HTTP.Port),
+		lookupDuration("HTTP_REQUEST_TIMEOUT", &c.HTTP.RequestTimeout),
+		lookupDuration("HTTP_READ_HEADER_TIMEOUT", &c.HTTP.ReadHeaderTimeout),
+		lookupDuration("HTTP_READ_TIMEOUT", &c.HTTP.ReadTimeout),
+		lookupDuration("HTTP_WRITE_TIMEOUT", &c.HTTP.WriteTimeout),
+		lookupDuration("HTTP_IDLE_TIMEOUT", &c.HTTP.IdleTimeout),
+		lookupDuration("HTTP_SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout),
+		lookupString("MYSQL_HOST", &c.MySQL.Host),
+		lookupInt("MYSQL_PORT", &c.MySQL.Port),
+		lookupString("MYSQL_USER", &c.MySQL.User),
+		lookupString("MYSQL_PASSWORD", &c.MySQL.Password),
+		lookupString("MYSQL_DATABASE", &c.MySQL.Database),
+		lookupString("SQLITE_PATH", &c.SQLite.Path),
+		lookupInt("DB_MAX_OPEN_CONNS", &c.Pool.MaxOpenConns),
+		lookupInt("DB_MAX_IDLE_CONNS", &c.Pool.MaxIdleConns),
+		lookupDuration("DB_CONN_MAX_LIFETIME", &c.Pool.ConnMaxLifetime),
+		lookupDuration("DB_CONN_MAX_IDLE_TIME", &c.Pool.ConnMaxIdleTime),
+		lookupInt("DB_CONNECT_ATTEMPTS", &c.Connect.Attempts),
+		lookupDuration("DB_CONNECT_BACKOFF", &c.Connect.Backoff),
+		lookupDuration("DB_CONNECT_MAX_BACKOFF", &c.Connect.MaxBackoff),
+		lookupDuration("DB_PING_TIMEOUT", &c.Connect.PingTimeout),
+		lookupInt("BCRYPT_COST", &c.Auth.BcryptCost),
+		lookupString("JWT_SECRET", &c.Auth.JWTSecret),
+		lookupString("JWT_ISSUER", &c.Auth.JWTIssuer),
+		lookupDuration("ACCESS_TOKEN_TTL", &c.Auth.AccessTokenTTL),
+		lookupDuration("REFRESH_TOKEN_TTL", &c.Auth.RefreshTokenTTL),
+		lookupString("ADMIN_EMAIL", &c.Auth.AdminEmail),
+		lookupString("ADMIN_PASSWORD", &c.Auth.AdminPassword),
+		lookupString("PAYMENT_GATEWAY", &c.Payment.Gateway),
+		lookupString("PAYMENT_FAKE_OUTCOME", &c.Payment.FakeOutcome),
+		lookupDuration("PAYMENT_FAKE_LATENCY", &c.Payment.FakeLatency),
+		lookupDuration("PAYMENT_RETRY_AFTER", &c.Payment.RetryAfter),
+		lookupString("PAYMENT_WEBHOOK_SECRET", &c.Payment.WebhookSecret),
+		lookupDuration("HEALTH_CHECK_TIMEOUT", &c.Health.CheckTimeout),
+		lookupString("TRACING_EXPORTER", &c.Tracing.Exporter),
+		lookupString("TRACING_ENDPOINT", &c.Tracing.Endpoint),
+		lookupString("TRACING_FILE", &c.Tracing.File),
+		lookupString("TRACING_SERVICE_NAME", &c.Tracing.ServiceName),
+		lookupFloat("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio),
+		lookupString("LOG_LEVEL", &c.Log.Level),
+		lookupString("LOG_FORMAT", &c.Log.Format),
+		lookupBool("MIGRATE_ON_START", &c.MigrateOnStart),
+	)
+
+	// docker-compose.yml only sets MYSQL_ROOT_PASSWORD for the root account.
+	if _, ok := os.LookupEnv("MYSQL_PASSWORD"); !ok && c.MySQL.User == "root" {
+		if password, ok := os.LookupEnv("MYSQL_ROOT_PASSWORD"); ok {
+			c.MySQL.Password = password
+		}
+	}
+	return err
+}
+
+func (c Config) Validate() error {
+	var errs []error
+	if c.HTTP.Port < 1 || c.HTTP.Port > 65535 {
+		errs = append(errs, fmt.Errorf("http.port must be between 1 and 65535, got %d", c.HTTP.Port))
+	}
+	if c.HTTP.RequestTimeout <= 0 {
+		errs = append(errs, errors.New("http.request_timeout must be positive"))
+	}
+	if c.HTTP.ReadHeaderTimeout <= 0 || c.HTTP.ReadTimeout <= 0 || c.HTTP.IdleTimeout <= 0 || c.HTTP.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("http read, idle and shutdown timeouts must be positive"))
+	}
+	// a handler cut off by RequestTimeout still needs time to write its error response
+	if c.HTTP.WriteTimeout <= c.HTTP.RequestTimeout {
+		errs = append(errs, fmt.Errorf("http.write_timeout (%s) must exceed http.request_timeout (%s)", c.HTTP.WriteTimeout, c.HTTP.RequestTimeout))
+	}
+	switch c.Storage {
+	case StorageMySQL:
+		if c.MySQL.Host == "" {
+			errs = append(errs, errors.New("mysql.host is required"))
+		}
+		if c.MySQL.Port < 1 || c.MySQL.Port > 65535 {
+			errs = append(errs, fmt.Errorf("mysql.port must be between 1 and 65535, got %d", c.MySQL.Port))
+		}
+		if c.MySQL.User == "" {
+			errs = append(errs, errors.New("mysql.user is required"))
+		}
+		if c.MySQL.Password == "" {
+			errs = append(errs, errors.New("mysql.password is required"))
+		}
+		if c.MySQL.Database == "" {
+			errs = append(errs, errors.New("mysql.database is required"))
+		}
+	case StorageSQLite:
+		if c.SQLite.Path == "" {
+			errs = append(errs, errors.New("sqlite.path is required"))
+		}
+	case StorageMemory:
+	default:
+		errs = append(errs, fmt.Errorf("storage must be %q, %q or %q, got %q", StorageMySQL, StorageSQLite, StorageMemory, c.Storage))
+	}
+	if c.Pool.MaxOpenConns < 0 || c.Pool.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("pool connection limits must not be negative"))
+	}
+	if c.Pool.MaxOpenConns > 0 && c.Pool.MaxIdleConns > c.Pool.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("pool.max_idle_conns (%d) must not exceed pool.max_open_conns (%d)", c.Pool.MaxIdleConns, c.Pool.MaxOpenConns))
+	}
+	if c.Pool.ConnMaxLifetime < 0 || c.Pool.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("pool connection lifetimes must not be negative"))
+	}
+	if c.Connect.Attempts < 1 {
+		errs = append(errs, fmt.Errorf("connect.attempts must be at least 1, got %d", c.Connect.Attempts))
+	}
+	if c.Connect.Backoff <= 0 || c.Connect.MaxBackoff < c.Connect.Backoff {
+		errs = append(errs, errors.New("connect.backoff must be positive and not exceed connect.max_backoff"))
+	}
+	if c.Connect.PingTimeout <= 0 {
+		errs = append(errs, errors.New("connect.ping_timeout must be positive"))
+	}
+	if c.Auth.BcryptCost < 4 || c.Auth.BcryptCost > 31 {
+		errs = append(errs, fmt.Errorf("auth.bcrypt_cost must be between 4 and 31, got %d", c.Auth.BcryptCost))
+	}
+	// an empty secret is allowed and replaced by a random one at startup
+	if c.Auth.JWTSecret != "" && len(c.Auth.JWTSecret) < 32 {
+		errs = append(errs, errors.New("auth.jwt_secret must be at least 32 bytes"))
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		errs = append(errs, errors.New("auth token ttls must be positive"))
+	}
+	if (c.Auth.AdminEmail == "") != (c.Auth.AdminPassword == "") {
+		errs = append(errs, errors.New("auth.admin_email and auth.admin_password must be set together"))
+	}
+	if c.Payment.Gateway != PaymentGatewayFake {
+		errs = append(errs, fmt.Errorf("payment.gateway must be %q, got %q", PaymentGatewayFake, c.Payment.Gateway))
+	}
+	switch c.Payment.FakeOutcome {
+	case "approve", "decline", "timeout":
+	default:
+		errs = append(errs, fmt.Errorf("payment.fake_outcome must be approve, decline or timeout, got %q", c.Payment.FakeOutcome))
+	}
+	if c.Payment.FakeLatency < 0 {
+		errs = append(errs, errors.New("payment.fake_latency must not be negative"))
+	}
+	if c.Payment.RetryAfter <= 0 {
+		errs = append(errs, errors.New("payment.retry_after must be positive"))
+	}
+	if c.Payment.WebhookSecret != "" && len(c.Payment.WebhookSecret) < 32 {
+		errs = append(errs, errors.New("payment.webhook_secret must be at least 32 bytes"))
+	}
+	if c.Health.CheckTimeout <= 0 {
+		errs = append(errs, errors.New("health.check_timeout must be positive"))
+	}
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingOTLP:
+		if c.Tracing.Endpoint == "" {
+			errs = append(errs, errors.New("tracing.endpoint is required for the otlp exporter"))
+		}
+	case TracingFile:
+		if c.Tracing.File == "" {
+			errs = append(errs, errors.New("tracing.file is required for the file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be %q, %q, %q or %q, got %q", TracingNone, TracingOTLP, TracingStdout, TracingFile, c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log.level must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	if c.Log.Format != LogJSON && c.Log.Format != LogText {
+		errs = append(errs, fmt.Errorf("log.format must be %q or %q, got %q", LogJSON, LogText, c.Log.Format))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func (c HTTPConfig) Addr() string {
+	return c.Host + ":" + strconv.Itoa(c.Port)
+}
+
+func (c MySQLConfig) DSN() string {
+	dsn := mysql.NewConfig()
+	dsn.User = c.User
+	dsn.Passwd = c.Password
+	dsn.Net = "tcp"
+	dsn.Addr = c.Host + ":" + strconv.Itoa(c.Port)
+	dsn.DBName = c.Database
+	dsn.ParseTime = true
+	// report matched rather than changed rows, so an UPDATE that finds its row never looks like a miss
+	dsn.ClientFoundRows = true
+	return dsn.FormatDSN()
+}
+
+// SlogLevel returns the parsed Level; Validate has already checked it.
+func (c LogConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.Level))
+	return level
+}
+
+func (c SQLiteConfig) DSN() string {
+	return "file:" + c.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+func lookupString(key string, dst *string) error {
+	if value, ok := os.LookupEnv(key); ok {
+		*dst = value
+	}
+	return nil
+}
+
+func lookupInt(key string, dst *int) error {
+	value, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("%s: %q is not an integer", key, value)
+	}
+	*dst = parsed
+	return nil
+}
+
+func lookupBool(key string, dst *bool) error {
+	value, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a boolean", key, value)
+	}
+	*dst = parsed
+	return nil
+}
+
+func lookupFloat(key string, dst *float64) error {
+	value, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a number", key, value)
+	}
+	*dst = parsed
+	return nil
+}
+
+func lookupDuration(key string, dst *time.Duration) error {
+	value, ok := os.LookupEnv(key)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a duration", key, value)
+	}
+	*dst = parsed
+	return nil
+}
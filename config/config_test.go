@@ -0,0 +1,272 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(t *testing.T, c Config)
+		wantErr []string
+	}{
+		{
+			name: "no variables keeps the defaults",
+			check: func(t *testing.T, c Config) {
+				if c.HTTP.Port != 3030 || c.Storage != StorageMySQL || c.MySQL.User != "" || c.MySQL.Password != "" {
+					t.Errorf("defaults changed: %+v", c)
+				}
+			},
+		},
+		{
+			name: "typed values",
+			env: map[string]string{
+				"STORAGE":              "sqlite",
+				"HTTP_PORT":            " 8080 ",
+				"HTTP_REQUEST_TIMEOUT": "3s",
+				"MYSQL_USER":           "app",
+				"MYSQL_PASSWORD":       "secret",
+				"MIGRATE_ON_START":     "false",
+				"TRACING_SAMPLE_RATIO": "0.25",
+				"LOG_LEVEL":            "debug",
+			},
+			check: func(t *testing.T, c Config) {
+				if c.Storage != StorageSQLite {
+					t.Errorf("Storage = %q, want sqlite", c.Storage)
+				}
+				if c.HTTP.Port != 8080 {
+					t.Errorf("HTTP.Port = %d, want 8080", c.HTTP.Port)
+				}
+				if c.HTTP.RequestTimeout != 3*time.Second {
+					t.Errorf("HTTP.RequestTimeout = %s, want 3s", c.HTTP.RequestTimeout)
+				}
+				if c.MySQL.User != "app" || c.MySQL.Password != "secret" {
+					t.Errorf("MySQL credentials = %q/%q, want app/secret", c.MySQL.User, c.MySQL.Password)
+				}
+				if c.MigrateOnStart {
+					t.Error("MigrateOnStart = true, want false")
+				}
+				if c.Tracing.SampleRatio != 0.25 {
+					t.Errorf("Tracing.SampleRatio = %g, want 0.25", c.Tracing.SampleRatio)
+				}
+				if c.Log.Level != "debug" {
+					t.Errorf("Log.Level = %q, want debug", c.Log.Level)
+				}
+			},
+		},
+		{
+			name: "blank numbers are ignored",
+			env:  map[string]string{"HTTP_PORT": " ", "HTTP_REQUEST_TIMEOUT": ""},
+			check: func(t *testing.T, c Config) {
+				if c.HTTP.Port != 3030 || c.HTTP.RequestTimeout != 10*time.Second {
+					t.Errorf("HTTP = %+v, want the defaults", c.HTTP)
+				}
+			},
+		},
+		{
+			name: "root falls back to MYSQL_ROOT_PASSWORD",
+			env:  map[string]string{"MYSQL_USER": "root", "MYSQL_ROOT_PASSWORD": "rootpw"},
+			check: func(t *testing.T, c Config) {
+				if c.MySQL.Password != "rootpw" {
+					t.Errorf("MySQL.Password = %q, want rootpw", c.MySQL.Password)
+				}
+			},
+		},
+		{
+			name: "MYSQL_PASSWORD wins over MYSQL_ROOT_PASSWORD",
+			env:  map[string]string{"MYSQL_USER": "root", "MYSQL_PASSWORD": "userpw", "MYSQL_ROOT_PASSWORD": "rootpw"},
+			check: func(t *testing.T, c Config) {
+				if c.MySQL.Password != "userpw" {
+					t.Errorf("MySQL.Password = %q, want userpw", c.MySQL.Password)
+				}
+			},
+		},
+		{
+			name: "other users ignore MYSQL_ROOT_PASSWORD",
+			env:  map[string]string{"MYSQL_USER": "app", "MYSQL_ROOT_PASSWORD": "rootpw"},
+			check: func(t *testing.T, c Config) {
+				if c.MySQL.Password != "" {
+					t.Errorf("MySQL.Password = %q, want it unset", c.MySQL.Password)
+				}
+			},
+		},
+		{
+			name: "every malformed value is reported",
+			env: map[string]string{
+				"HTTP_PORT":            "eighty",
+				"HTTP_IDLE_TIMEOUT":    "60",
+				"MIGRATE_ON_START":     "sometimes",
+				"TRACING_SAMPLE_RATIO": "half",
+			},
+			wantErr: []string{"HTTP_PORT", "HTTP_IDLE_TIMEOUT", "MIGRATE_ON_START", "TRACING_SAMPLE_RATIO"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// start from a clean slate whatever the shell running the tests exports
+			for _, key := range []string{"STORAGE", "HTTP_PORT", "HTTP_REQUEST_TIMEOUT", "HTTP_IDLE_TIMEOUT", "MYSQL_USER",
+				"MYSQL_PASSWORD", "MYSQL_ROOT_PASSWORD", "MIGRATE_ON_START", "TRACING_SAMPLE_RATIO", "LOG_LEVEL"} {
+				t.Setenv(key, "")
+				os.Unsetenv(key)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg := Default()
+			err := cfg.loadEnv()
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("loadEnv succeeded, want an error")
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("loadEnv error %q does not mention %s", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Config {
+		cfg := Default()
+		cfg.MySQL.User = "app"
+		cfg.MySQL.Password = "secret"
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		wantErr []string
+	}{
+		{name: "valid", mutate: func(c *Config) {}},
+		{
+			name:    "mysql without credentials",
+			mutate:  func(c *Config) { c.MySQL.User, c.MySQL.Password = "", "" },
+			wantErr: []string{"mysql.user is required", "mysql.password is required"},
+		},
+		{
+			name:    "mysql without password",
+			mutate:  func(c *Config) { c.MySQL.Password = "" },
+			wantErr: []string{"mysql.password is required"},
+		},
+		{
+			name: "sqlite needs no mysql credentials",
+			mutate: func(c *Config) {
+				c.Storage = StorageSQLite
+				c.MySQL.User, c.MySQL.Password = "", ""
+			},
+		},
+		{
+			name: "memory needs no mysql credentials",
+			mutate: func(c *Config) {
+				c.Storage = StorageMemory
+				c.MySQL.User, c.MySQL.Password = "", ""
+			},
+		},
+		{
+			name:    "sqlite without path",
+			mutate:  func(c *Config) { c.Storage, c.SQLite.Path = StorageSQLite, "" },
+			wantErr: []string{"sqlite.path is required"},
+		},
+		{
+			name:    "unknown storage",
+			mutate:  func(c *Config) { c.Storage = "postgres" },
+			wantErr: []string{"storage must be"},
+		},
+		{
+			name:    "port out of range",
+			mutate:  func(c *Config) { c.HTTP.Port = 70000 },
+			wantErr: []string{"http.port"},
+		},
+		{
+			name:    "write timeout within request timeout",
+			mutate:  func(c *Config) { c.HTTP.WriteTimeout = c.HTTP.RequestTimeout },
+			wantErr: []string{"http.write_timeout"},
+		},
+		{
+			name:    "idle pool larger than open pool",
+			mutate:  func(c *Config) { c.Pool.MaxOpenConns, c.Pool.MaxIdleConns = 5, 10 },
+			wantErr: []string{"pool.max_idle_conns"},
+		},
+		{
+			name:    "backoff above max backoff",
+			mutate:  func(c *Config) { c.Connect.Backoff = time.Minute },
+			wantErr: []string{"connect.backoff"},
+		},
+		{
+			name:    "short jwt secret",
+			mutate:  func(c *Config) { c.Auth.JWTSecret = "too-short" },
+			wantErr: []string{"auth.jwt_secret"},
+		},
+		{
+			name:    "admin email without password",
+			mutate:  func(c *Config) { c.Auth.AdminEmail = "admin@example.com" },
+			wantErr: []string{"auth.admin_email and auth.admin_password"},
+		},
+		{
+			name:    "unknown fake outcome",
+			mutate:  func(c *Config) { c.Payment.FakeOutcome = "maybe" },
+			wantErr: []string{"payment.fake_outcome"},
+		},
+		{
+			name:    "payment retry after zero",
+			mutate:  func(c *Config) { c.Payment.RetryAfter = 0 },
+			wantErr: []string{"payment.retry_after"},
+		},
+		{
+			name:    "otlp without endpoint",
+			mutate:  func(c *Config) { c.Tracing.Exporter, c.Tracing.Endpoint = TracingOTLP, "" },
+			wantErr: []string{"tracing.endpoint"},
+		},
+		{
+			name:    "sample ratio above one",
+			mutate:  func(c *Config) { c.Tracing.SampleRatio = 1.5 },
+			wantErr: []string{"tracing.sample_ratio"},
+		},
+		{
+			name:    "unknown log level and format",
+			mutate:  func(c *Config) { c.Log.Level, c.Log.Format = "loud", "xml" },
+			wantErr: []string{"log.level", "log.format"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.mutate(&cfg)
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate succeeded, want an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultNeedsMySQLCredentials(t *testing.T) {
+	err := Default().Validate()
+	if err == nil || !strings.Contains(err.Error(), "mysql.user is required") || !strings.Contains(err.Error(), "mysql.password is required") {
+		t.Errorf("Default().Validate() = %v, want missing mysql credentials", err)
+	}
+}
@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type TokenKind string
+
+const (
+	AccessToken  TokenKind = "access"
+	RefreshToken TokenKind = "refresh"
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	UserId int    `json:"user_id"`
+	Email  string `json:"email"`
+	Role   Role   `json:"role"`
+}
+
+func (i Identity) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		if i.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	TokenType        string    `json:"token_type"`
+}
+
+// LogValue leaves the tokens themselves out of log lines.
+func (p TokenPair) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Time("access_expires_at", p.AccessExpiresAt),
+		slog.Time("refresh_expires_at", p.RefreshExpiresAt),
+		slog.String("token_type", p.TokenType),
+	)
+}
+
+type identityKey struct{}
+
+func ContextWithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok && identity != nil
+}
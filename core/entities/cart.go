@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// Cart is what a user intends to buy. Items carry the current title and price
+// of their product, so Total is what checking out would charge right now.
+type Cart struct {
+	UserId uint       `json:"user_id"`
+	Items  []CartItem `json:"items"`
+	// Total has no currency while the cart is empty.
+	Total Money `json:"total"`
+}
+
+type CartItem struct {
+	ProductId uint      `json:"product_id"`
+	Title     string    `json:"title"`
+	Quantity  uint      `json:"quantity"`
+	UnitPrice Money     `json:"unit_price"`
+	AddedAt   time.Time `json:"added_at"`
+}
+
+func (i CartItem) Subtotal() (Money, error) {
+	return i.UnitPrice.Mul(int64(i.Quantity))
+}
+
+// ComputeTotal sets Total to the sum of the item subtotals. It fails if the
+// items are priced in different currencies.
+func (c *Cart) ComputeTotal() error {
+	total, err := sumSubtotals(c.Items, CartItem.Subtotal)
+	if err != nil {
+		return err
+	}
+	c.Total = total
+	return nil
+}
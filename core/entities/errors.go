@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies failures so that every inbound adapter can report them
+// the same way. A kind is itself an error, usable as an errors.Is target:
+//
+//	if errors.Is(err, entities.ErrNotFound) { ... }
+type ErrorKind string
+
+const (
+	ErrNotFound     ErrorKind = "not_found"
+	ErrConflict     ErrorKind = "conflict"
+	ErrValidation   ErrorKind = "validation"
+	ErrUnauthorized ErrorKind = "unauthorized"
+	ErrForbidden    ErrorKind = "forbidden"
+	// ErrPaymentDeclined is a payment the gateway refused.
+	ErrPaymentDeclined ErrorKind = "payment_declined"
+)
+
+func (k ErrorKind) Error() string {
+	return string(k)
+}
+
+// Error is a domain error of a given kind, optionally wrapping its cause.
+// Fields lists the offending input fields of a validation error.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(ErrorKind)
+	return ok && kind == e.Kind
+}
+
+func NotFound(format string, args ...any) *Error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...any) *Error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+func Validation(format string, args ...any) *Error {
+	return &Error{Kind: ErrValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+func Unauthorized(format string, args ...any) *Error {
+	return &Error{Kind: ErrUnauthorized, Message: fmt.Sprintf(format, args...)}
+}
+
+func Forbidden(format string, args ...any) *Error {
+	return &Error{Kind: ErrForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+func Declined(format string, args ...any) *Error {
+	return &Error{Kind: ErrPaymentDeclined, Message: fmt.Sprintf(format, args...)}
+}
+
+// KindOf returns the kind of err, or "" for errors outside the taxonomy.
+func KindOf(err error) ErrorKind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	var kind ErrorKind
+	if errors.As(err, &kind) {
+		return kind
+	}
+	return ""
+}
+
+// Fields returns the field errors carried by err, if any.
+func Fields(err error) []FieldError {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Fields
+	}
+	return nil
+}
+
+// Message is the client-safe text of err: the domain message, or "" for errors outside the taxonomy.
+func Message(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	var kind ErrorKind
+	if errors.As(err, &kind) {
+		return string(kind)
+	}
+	return ""
+}
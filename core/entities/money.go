@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Currency is an ISO 4217 code of a currency we sell in.
+type Currency string
+
+const (
+	THB Currency = "THB"
+	USD Currency = "USD"
+)
+
+func (c Currency) Valid() bool {
+	return c == THB || c == USD
+}
+
+// Exponent is the number of decimal places of the currency: two for both baht
+// (100 satang) and dollars (100 cents).
+func (c Currency) Exponent() int {
+	return 2
+}
+
+// MinorUnits is how many minor units make one major unit.
+func (c Currency) MinorUnits() int64 {
+	units := int64(1)
+	for range c.Exponent() {
+		units *= 10
+	}
+	return units
+}
+
+// ErrCurrencyMismatch is wrapped by every error from combining amounts in
+// different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an exact amount in the minor units of its currency, so 12.50 baht
+// is Money{Amount: 1250, Currency: THB}. Stored as two columns, amount and
+// currency.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney reads a decimal amount in major units, such as "12.5", rounding
+// to the nearest minor unit with halves away from zero.
+func ParseMoney(amount string, currency Currency) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, Validation("%q is not a decimal amount", amount)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(currency.MinorUnits()))
+	minor, ok := roundHalfAway(r)
+	if !ok {
+		return Money{}, Validation("amount %s is too large", amount)
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, m.overflow()
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	diff := m.Amount - other.Amount
+	if (other.Amount > 0 && diff > m.Amount) || (other.Amount < 0 && diff < m.Amount) {
+		return Money{}, m.overflow()
+	}
+	return Money{Amount: diff, Currency: m.Currency}, nil
+}
+
+// Mul multiplies by a whole number, such as a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(n))
+	if !product.IsInt64() {
+		return Money{}, m.overflow()
+	}
+	return Money{Amount: product.Int64(), Currency: m.Currency}, nil
+}
+
+// MulRatio multiplies by num/den, rounding to the nearest minor unit with
+// halves away from zero; 7% VAT on m is m.MulRatio(7, 100).
+func (m Money) MulRatio(num, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, errors.New("money: ratio with a zero denominator")
+	}
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num)), big.NewInt(den))
+	amount, ok := roundHalfAway(r)
+	if !ok {
+		return Money{}, m.overflow()
+	}
+	return Money{Amount: amount, Currency: m.Currency}, nil
+}
+
+// String formats m in major units, such as "12.50 THB".
+func (m Money) String() string {
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(m.Amount))
+	major, minor := abs.QuoRem(abs, big.NewInt(m.Currency.MinorUnits()), new(big.Int))
+	return fmt.Sprintf("%s%s.%0*d %s", sign, major, m.Currency.Exponent(), minor, m.Currency)
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return &Error{Kind: ErrValidation, Message: fmt.Sprintf("cannot combine %s with %s", m.Currency, other.Currency), Err: ErrCurrencyMismatch}
+	}
+	return nil
+}
+
+func (m Money) overflow() error {
+	return Validation("%s amount is out of range", m.Currency)
+}
+
+// roundHalfAway rounds r to an integer, halves away from zero, and reports
+// whether the result fits in an int64.
+func roundHalfAway(r *big.Rat) (int64, bool) {
+	num, den := new(big.Int).Abs(r.Num()), r.Denom()
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64(), q.IsInt64()
+}
@@ -0,0 +1,170 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func thb(amount int64) Money {
+	return Money{Amount: amount, Currency: THB}
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		sum     Money
+		diff    Money
+		sumErr  bool
+		diffErr bool
+	}{
+		{name: "small", a: thb(1250), b: thb(75), sum: thb(1325), diff: thb(1175)},
+		{name: "negative", a: thb(-100), b: thb(250), sum: thb(150), diff: thb(-350)},
+		{name: "max plus one", a: thb(math.MaxInt64), b: thb(1), sumErr: true, diff: thb(math.MaxInt64 - 1)},
+		{name: "min minus one", a: thb(math.MinInt64), b: thb(1), sum: thb(math.MinInt64 + 1), diffErr: true},
+		{name: "max minus min", a: thb(math.MaxInt64), b: thb(math.MinInt64), sum: thb(-1), diffErr: true},
+		{name: "zero minus min", a: thb(0), b: thb(math.MinInt64), sum: thb(math.MinInt64), diffErr: true},
+		{name: "min plus min", a: thb(math.MinInt64), b: thb(math.MinInt64), sumErr: true, diff: thb(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, err := tt.a.Add(tt.b)
+			checkMoney(t, "Add", sum, err, tt.sum, tt.sumErr)
+			diff, err := tt.a.Sub(tt.b)
+			checkMoney(t, "Sub", diff, err, tt.diff, tt.diffErr)
+		})
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	usd := Money{Amount: 100, Currency: USD}
+	for name, op := range map[string]func(Money) (Money, error){"Add": thb(100).Add, "Sub": thb(100).Sub} {
+		_, err := op(usd)
+		if !errors.Is(err, ErrCurrencyMismatch) || !errors.Is(err, ErrValidation) {
+			t.Errorf("%s of USD to THB = %v, want ErrCurrencyMismatch as ErrValidation", name, err)
+		}
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		n       int64
+		want    Money
+		wantErr bool
+	}{
+		{name: "quantity", m: thb(1250), n: 3, want: thb(3750)},
+		{name: "zero", m: thb(1250), n: 0, want: thb(0)},
+		{name: "negative", m: thb(1250), n: -2, want: thb(-2500)},
+		{name: "max by one", m: thb(math.MaxInt64), n: 1, want: thb(math.MaxInt64)},
+		{name: "max by two", m: thb(math.MaxInt64), n: 2, wantErr: true},
+		{name: "min by minus one", m: thb(math.MinInt64), n: -1, wantErr: true},
+		{name: "wraps to a plausible amount", m: thb(1 << 62), n: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Mul(tt.n)
+			checkMoney(t, "Mul", got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestMoneyMulRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		num, den int64
+		want     Money
+		wantErr  bool
+	}{
+		{name: "7% VAT", m: thb(10000), num: 7, den: 100, want: thb(700)},
+		{name: "half rounds up", m: thb(50), num: 7, den: 100, want: thb(4)},         // 3.5
+		{name: "below half rounds down", m: thb(49), num: 7, den: 100, want: thb(3)}, // 3.43
+		{name: "negative half rounds away from zero", m: thb(-50), num: 7, den: 100, want: thb(-4)},
+		{name: "third", m: thb(100), num: 1, den: 3, want: thb(33)},
+		{name: "two thirds", m: thb(100), num: 2, den: 3, want: thb(67)},
+		{name: "negative denominator", m: thb(100), num: 1, den: -4, want: thb(-25)},
+		{name: "large intermediate", m: thb(math.MaxInt64), num: 3, den: 3, want: thb(math.MaxInt64)},
+		{name: "too large", m: thb(math.MaxInt64), num: 3, den: 2, wantErr: true},
+		{name: "zero denominator", m: thb(100), num: 1, den: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.MulRatio(tt.num, tt.den)
+			checkMoney(t, "MulRatio", got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "12.5", want: 1250},
+		{in: "12.50", want: 1250},
+		{in: " 12 ", want: 1200},
+		{in: "0.005", want: 1},
+		{in: "0.0049", want: 0},
+		{in: "0.015", want: 2},
+		{in: "0.025", want: 3},
+		{in: "-0.005", want: -1},
+		{in: "-0.0049", want: 0},
+		{in: "1e2", want: 10000},
+		{in: "92233720368547758.07", want: math.MaxInt64},
+		{in: "92233720368547758.08", wantErr: true},
+		{in: "-92233720368547758.08", want: math.MinInt64},
+		{in: "", wantErr: true},
+		{in: "12,50", wantErr: true},
+		{in: "twelve", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMoney(tt.in, THB)
+			checkMoney(t, "ParseMoney", got, err, thb(tt.want), tt.wantErr)
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{m: thb(1250), want: "12.50 THB"},
+		{m: thb(5), want: "0.05 THB"},
+		{m: thb(0), want: "0.00 THB"},
+		{m: thb(-5), want: "-0.05 THB"},
+		{m: thb(-1250), want: "-12.50 THB"},
+		{m: Money{Amount: 100, Currency: USD}, want: "1.00 USD"},
+		{m: thb(math.MaxInt64), want: "92233720368547758.07 THB"},
+		{m: thb(math.MinInt64), want: "-92233720368547758.08 THB"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("Money{%d, %s}.String() = %q, want %q", tt.m.Amount, tt.m.Currency, got, tt.want)
+		}
+	}
+}
+
+// checkMoney compares the result of op with want, or expects a validation
+// error when wantErr is set.
+func checkMoney(t *testing.T, op string, got Money, err error, want Money, wantErr bool) {
+	t.Helper()
+	if wantErr {
+		if err == nil {
+			t.Errorf("%s = %v, want an error", op, got)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("%s: %v", op, err)
+		return
+	}
+	if got != want {
+		t.Errorf("%s = %v, want %v", op, got, want)
+	}
+}
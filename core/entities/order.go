@@ -1,6 +1,91 @@
 package entities
 
+import "time"
+
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderPaid      OrderStatus = "paid"
+	OrderShipped   OrderStatus = "shipped"
+	OrderDelivered OrderStatus = "delivered"
+	OrderCancelled OrderStatus = "cancelled"
+)
+
+// orderTransitions lists the statuses each status may move to. Delivered and
+// cancelled orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderPending: {OrderPaid, OrderCancelled},
+	OrderPaid:    {OrderShipped, OrderCancelled},
+	OrderShipped: {OrderDelivered},
+}
+
+func (s OrderStatus) Valid() bool {
+	switch s {
+	case OrderPending, OrderPaid, OrderShipped, OrderDelivered, OrderCancelled:
+		return true
+	}
+	return false
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Order struct {
-	UserId    uint `json:"user_id"`
-	ProductId uint `json:"product_id"`
+	Id        int         `json:"id"`
+	UserId    uint        `json:"user_id"`
+	Status    OrderStatus `json:"status"`
+	Items     []OrderItem `json:"items"`
+	Total     Money       `json:"total"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// OrderItem is one line of an order. Title and UnitPrice are copied from the
+// product when the order is placed, so later product changes leave it alone.
+type OrderItem struct {
+	Id        int    `json:"id"`
+	ProductId uint   `json:"product_id"`
+	Title     string `json:"title"`
+	Quantity  uint   `json:"quantity"`
+	UnitPrice Money  `json:"unit_price"`
+}
+
+func (i OrderItem) Subtotal() (Money, error) {
+	return i.UnitPrice.Mul(int64(i.Quantity))
+}
+
+// ComputeTotal sets Total to the sum of the item subtotals. It fails if the
+// items are priced in different currencies.
+func (o *Order) ComputeTotal() error {
+	total, err := sumSubtotals(o.Items, OrderItem.Subtotal)
+	if err != nil {
+		return err
+	}
+	o.Total = total
+	return nil
+}
+
+// sumSubtotals adds up the subtotals of items, in the currency of the first.
+func sumSubtotals[T any](items []T, subtotal func(T) (Money, error)) (Money, error) {
+	var total Money
+	for i, item := range items {
+		amount, err := subtotal(item)
+		if err != nil {
+			return Money{}, err
+		}
+		if i == 0 {
+			total.Currency = amount.Currency
+		}
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
 }
@@ -0,0 +1,44 @@
+package entities
+
+import "testing"
+
+func TestOrderStatusCanTransitionTo(t *testing.T) {
+	statuses := []OrderStatus{OrderPending, OrderPaid, OrderShipped, OrderDelivered, OrderCancelled}
+	allowed := map[[2]OrderStatus]bool{
+		{OrderPending, OrderPaid}:      true,
+		{OrderPending, OrderCancelled}: true,
+		{OrderPaid, OrderShipped}:      true,
+		{OrderPaid, OrderCancelled}:    true,
+		{OrderShipped, OrderDelivered}: true,
+	}
+
+	// every pair, so that a transition added to orderTransitions without a
+	// matching entry above fails too
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]OrderStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	for _, from := range statuses {
+		if from.CanTransitionTo("refunded") || OrderStatus("refunded").CanTransitionTo(from) {
+			t.Errorf("unknown status moves to or from %s", from)
+		}
+	}
+}
+
+func TestOrderStatusValid(t *testing.T) {
+	for _, status := range []OrderStatus{OrderPending, OrderPaid, OrderShipped, OrderDelivered, OrderCancelled} {
+		if !status.Valid() {
+			t.Errorf("%s.Valid() = false, want true", status)
+		}
+	}
+	for _, status := range []OrderStatus{"", "Pending", "refunded"} {
+		if status.Valid() {
+			t.Errorf("%q.Valid() = true, want false", status)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package entities
+
+import (
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// PageRequest selects one page of a sorted list. Cursor is opaque to clients;
+// Normalize decodes it into Offset.
+type PageRequest struct {
+	Limit     int
+	Cursor    string
+	Sort      string
+	Direction SortDirection
+	Offset    int
+}
+
+// PageInfo describes the page that was returned. NextCursor is empty on the last page.
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Sortable fields, named like the JSON fields and the table columns
+var (
+	UserSortFields    = []string{"id", "username", "email", "created_at"}
+	ProductSortFields = []string{"id", "title", "price", "created_at"}
+)
+
+type UserQuery struct {
+	PageRequest
+	Role   Role
+	Search string // matched against username and email
+}
+
+// ProductQuery filters products. Price bounds are in minor units of Currency,
+// which they require.
+type ProductQuery struct {
+	PageRequest
+	Currency      Currency
+	MinPrice      int64
+	MaxPrice      int64 // 0 means no upper bound
+	TitleContains string
+}
+
+func (q *UserQuery) Normalize() error {
+	var v Violations
+	q.PageRequest.normalize(UserSortFields, &v)
+	if q.Role != "" && !q.Role.Valid() {
+		v.Add("role", "must be one of admin, staff, customer")
+	}
+	return v.Err()
+}
+
+func (q *ProductQuery) Normalize() error {
+	var v Violations
+	q.PageRequest.normalize(ProductSortFields, &v)
+	if q.Currency != "" && !q.Currency.Valid() {
+		v.Add("currency", "must be THB or USD")
+	}
+	if q.Currency == "" && (q.MinPrice != 0 || q.MaxPrice != 0) {
+		v.Add("currency", "is required with a price range")
+	}
+	if q.MinPrice < 0 || q.MaxPrice < 0 {
+		v.Add("min_price", "must not be negative")
+	}
+	if q.MaxPrice != 0 && q.MinPrice > q.MaxPrice {
+		v.Add("max_price", "must not be less than min_price")
+	}
+	return v.Err()
+}
+
+// normalize fills in the defaults and decodes the cursor.
+func (p *PageRequest) normalize(sortFields []string, v *Violations) {
+	switch {
+	case p.Limit == 0:
+		p.Limit = DefaultPageLimit
+	case p.Limit < 0 || p.Limit > MaxPageLimit:
+		v.Add("limit", "must be between 1 and "+strconv.Itoa(MaxPageLimit))
+	}
+	if p.Sort == "" {
+		p.Sort = "id"
+	} else if !slices.Contains(sortFields, p.Sort) {
+		v.Add("sort", "must be one of "+strings.Join(sortFields, ", "))
+	}
+	switch p.Direction {
+	case "":
+		p.Direction = SortAsc
+	case SortAsc, SortDesc:
+	default:
+		v.Add("order", "must be asc or desc")
+	}
+	offset, ok := decodeCursor(p.Cursor)
+	if !ok {
+		v.Add("cursor", "is invalid")
+	}
+	p.Offset = offset
+}
+
+// NewPageInfo describes a page of n items starting at the request's offset out of total.
+func NewPageInfo(p PageRequest, n, total int) PageInfo {
+	info := PageInfo{Limit: p.Limit, Total: total}
+	if next := p.Offset + n; n > 0 && next < total {
+		info.NextCursor = encodeCursor(next)
+	}
+	return info
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, bool) {
+	if cursor == "" {
+		return 0, true
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+	value, ok := strings.CutPrefix(string(raw), "offset:")
+	if !ok {
+		return 0, false
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, false
+	}
+	return offset, true
+}
@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+type PaymentStatus string
+
+const (
+	// PaymentPending is an authorisation the gateway has not decided yet.
+	PaymentPending    PaymentStatus = "pending"
+	PaymentAuthorized PaymentStatus = "authorized"
+	PaymentDeclined   PaymentStatus = "declined"
+)
+
+// Settled reports whether s is a final outcome from the gateway.
+func (s PaymentStatus) Settled() bool {
+	return s == PaymentAuthorized || s == PaymentDeclined
+}
+
+// Payment is one attempt to pay for an order. Reference identifies the attempt
+// to the gateway, which quotes it back in its callbacks.
+type Payment struct {
+	Id        int           `json:"id"`
+	OrderId   uint          `json:"order_id"`
+	Reference string        `json:"reference"`
+	Amount    Money         `json:"amount"`
+	Status    PaymentStatus `json:"status"`
+	Reason    string        `json:"reason,omitempty"`
+	// RefundDue marks an authorised payment whose order was cancelled, which
+	// staff must void or refund with the gateway.
+	RefundDue bool      `json:"refund_due,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PaymentResult is the gateway's decision on a payment, given either in reply
+// to the authorisation or later through a callback.
+type PaymentResult struct {
+	Reference string
+	Status    PaymentStatus
+	// Reason explains a decline.
+	Reason string
+}
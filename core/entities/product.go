@@ -1,7 +1,13 @@
 package entities
 
+import "time"
+
 type Product struct {
-	Title  string `json:"title"`
-	Price  uint   `json:"price"`
-	Detail string `json:"detail"`
+	Id        int       `json:"id"`
+	Title     string    `json:"title"`
+	Price     Money     `json:"price"`
+	Detail    string    `json:"detail"`
+	Stock     uint      `json:"stock"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,17 @@
+package entities
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleStaff    Role = "staff"
+	RoleCustomer Role = "customer"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleStaff, RoleCustomer:
+		return true
+	}
+	return false
+}
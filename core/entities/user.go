@@ -1,7 +1,26 @@
 package entities
 
+import (
+	"log/slog"
+	"time"
+)
+
 type User struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Id        int       `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Password  string    `json:"password"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LogValue leaves the password out of log lines, hashed or not.
+func (u User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("id", u.Id),
+		slog.String("username", u.Username),
+		slog.String("email", u.Email),
+		slog.String("role", string(u.Role)),
+	)
 }
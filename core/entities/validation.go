@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"net/mail"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxUsernameLength = 64
+	MaxEmailLength    = 255
+	MaxTitleLength    = 255
+	MinPasswordLength = 4
+	// bcrypt ignores everything past 72 bytes
+	MaxPasswordLength = 72
+	MaxItemQuantity   = 1000
+)
+
+// Violations collects field errors while checking an invariant.
+type Violations []FieldError
+
+func (v *Violations) Add(field, message string) {
+	*v = append(*v, FieldError{Field: field, Message: message})
+}
+
+// Err returns nil when nothing was added, otherwise a validation error listing every field.
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &Error{Kind: ErrValidation, Message: "invalid input", Fields: v}
+}
+
+// Validate checks the stored fields of a user; the plaintext password is checked
+// by the service before it is hashed.
+func (u *User) Validate() error {
+	var v Violations
+	switch {
+	case strings.TrimSpace(u.Username) == "":
+		v.Add("username", "is required")
+	case utf8.RuneCountInString(u.Username) > MaxUsernameLength:
+		v.Add("username", "is too long")
+	}
+	switch {
+	case u.Email == "":
+		v.Add("email", "is required")
+	case len(u.Email) > MaxEmailLength || !validEmail(u.Email):
+		v.Add("email", "must be a valid email address")
+	}
+	if !u.Role.Valid() {
+		v.Add("role", "must be one of admin, staff, customer")
+	}
+	return v.Err()
+}
+
+func (p *Product) Validate() error {
+	var v Violations
+	switch {
+	case strings.TrimSpace(p.Title) == "":
+		v.Add("title", "is required")
+	case utf8.RuneCountInString(p.Title) > MaxTitleLength:
+		v.Add("title", "is too long")
+	}
+	if !p.Price.Currency.Valid() {
+		v.Add("price.currency", "must be THB or USD")
+	}
+	if !p.Price.IsPositive() {
+		v.Add("price.amount", "must be greater than 0")
+	}
+	return v.Err()
+}
+
+func (o *Order) Validate() error {
+	var v Violations
+	if o.UserId == 0 {
+		v.Add("user_id", "is required")
+	}
+	if !o.Status.Valid() {
+		v.Add("status", "is invalid")
+	}
+	if len(o.Items) == 0 {
+		v.Add("items", "must not be empty")
+	}
+	seen := make(map[uint]bool, len(o.Items))
+	for i, item := range o.Items {
+		field := "items[" + strconv.Itoa(i) + "]"
+		switch {
+		case item.ProductId == 0:
+			v.Add(field+".product_id", "is required")
+		case seen[item.ProductId]:
+			v.Add(field+".product_id", "is listed twice")
+		}
+		seen[item.ProductId] = true
+		if item.Quantity == 0 || item.Quantity > MaxItemQuantity {
+			v.Add(field+".quantity", "must be between 1 and "+strconv.Itoa(MaxItemQuantity))
+		}
+	}
+	return v.Err()
+}
+
+func (i *CartItem) Validate() error {
+	var v Violations
+	if i.ProductId == 0 {
+		v.Add("product_id", "is required")
+	}
+	if i.Quantity == 0 || i.Quantity > MaxItemQuantity {
+		v.Add("quantity", "must be between 1 and "+strconv.Itoa(MaxItemQuantity))
+	}
+	return v.Err()
+}
+
+// validEmail accepts a bare address such as "alice@example.com", not "Alice <alice@example.com>".
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
@@ -0,0 +1,14 @@
+package port // primary port
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type AuthInbound interface {
+	Login(ctx context.Context, email, password string) (*entities.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*entities.TokenPair, error)
+	// Authenticate resolves the caller behind an access token.
+	Authenticate(ctx context.Context, accessToken string) (*entities.Identity, error)
+}
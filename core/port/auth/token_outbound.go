@@ -0,0 +1,14 @@
+package port // secondary port
+
+import (
+	"context"
+	"time"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+type TokenProvider interface {
+	Issue(ctx context.Context, identity entities.Identity, kind entities.TokenKind) (token string, expiresAt time.Time, err error)
+	// Parse verifies token and that it was issued as kind.
+	Parse(ctx context.Context, token string, kind entities.TokenKind) (*entities.Identity, error)
+}
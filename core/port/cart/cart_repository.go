@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// outbound
+type CartRepository interface {
+	// FindByUserId returns the user's items in the order they were first added.
+	// Only ProductId, Quantity and AddedAt are set.
+	FindByUserId(ctx context.Context, userId int) ([]entities.CartItem, error)
+	// SetItem puts quantity of the product in the user's cart, replacing the
+	// quantity already there but keeping its place.
+	SetItem(ctx context.Context, userId int, productId int, quantity uint) error
+	RemoveItem(ctx context.Context, userId int, productId int) error
+	Clear(ctx context.Context, userId int) error
+}
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// inbound; every method works on the cart of the caller in ctx.
+type CartService interface {
+	Get(ctx context.Context) (*entities.Cart, error)
+	// AddItem adds quantity of the product on top of what the cart holds.
+	AddItem(ctx context.Context, productId int, quantity uint) (*entities.Cart, error)
+	// UpdateItem sets the quantity of a product already in the cart.
+	UpdateItem(ctx context.Context, productId int, quantity uint) (*entities.Cart, error)
+	RemoveItem(ctx context.Context, productId int) (*entities.Cart, error)
+	Clear(ctx context.Context) error
+	// Checkout turns the cart into one order and empties it, all or nothing.
+	Checkout(ctx context.Context) (*entities.Order, error)
+}
@@ -0,0 +1,127 @@
+package contract
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/cart"
+	productPort "github.com/wittawat/go-hex/core/port/product"
+	userPort "github.com/wittawat/go-hex/core/port/user"
+)
+
+// CartStores is what a CartRepository needs around it: cart items reference
+// users and products, so the suite seeds those through the sibling ports.
+type CartStores struct {
+	Carts    port.CartRepository
+	Users    userPort.UserOutbound
+	Products productPort.ProductOutbound
+}
+
+func RunCartRepository(t *testing.T, newStores func(t *testing.T) CartStores) {
+	seed := func(t *testing.T) CartStores {
+		t.Helper()
+		stores := newStores(t)
+		mustSaveUser(t, stores.Users, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		mustSaveUser(t, stores.Users, entities.User{Username: "bob", Email: "bob@example.com", Password: "secret"})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "keyboard", Price: thb(1290)})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "mouse", Price: thb(590)})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "monitor", Price: thb(5900)})
+		return stores
+	}
+
+	t.Run("SetItemThenFindInAddOrder", func(t *testing.T) {
+		stores := seed(t)
+		mustSetItem(t, stores.Carts, 1, 3, 1)
+		mustSetItem(t, stores.Carts, 1, 1, 2)
+		mustSetItem(t, stores.Carts, 2, 2, 5)
+
+		got := findCart(t, stores.Carts, 1)
+		assertCart(t, got, 3, 1, 1, 2)
+		if got[0].AddedAt.IsZero() {
+			t.Fatal("SetItem left added_at unset")
+		}
+		assertCart(t, findCart(t, stores.Carts, 2), 2, 5)
+	})
+
+	t.Run("SetItemReplacesQuantityInPlace", func(t *testing.T) {
+		stores := seed(t)
+		mustSetItem(t, stores.Carts, 1, 3, 1)
+		mustSetItem(t, stores.Carts, 1, 1, 2)
+		mustSetItem(t, stores.Carts, 1, 3, 7)
+
+		assertCart(t, findCart(t, stores.Carts, 1), 3, 7, 1, 2)
+	})
+
+	t.Run("FindByUserIdWithEmptyCart", func(t *testing.T) {
+		if got := findCart(t, seed(t).Carts, 1); len(got) != 0 {
+			t.Fatalf("FindByUserId(1) returned %d items, want none", len(got))
+		}
+	})
+
+	t.Run("RemoveItem", func(t *testing.T) {
+		stores := seed(t)
+		mustSetItem(t, stores.Carts, 1, 1, 1)
+		mustSetItem(t, stores.Carts, 1, 2, 2)
+
+		if err := stores.Carts.RemoveItem(t.Context(), 1, 1); err != nil {
+			t.Fatalf("RemoveItem: %v", err)
+		}
+		assertCart(t, findCart(t, stores.Carts, 1), 2, 2)
+	})
+
+	t.Run("RemoveItemMissing", func(t *testing.T) {
+		stores := seed(t)
+		mustSetItem(t, stores.Carts, 2, 1, 1)
+		if err := stores.Carts.RemoveItem(t.Context(), 1, 1); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("RemoveItem of an item in another cart = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("ClearOnlyEmptiesOneCart", func(t *testing.T) {
+		stores := seed(t)
+		mustSetItem(t, stores.Carts, 1, 1, 1)
+		mustSetItem(t, stores.Carts, 1, 2, 1)
+		mustSetItem(t, stores.Carts, 2, 3, 4)
+
+		if err := stores.Carts.Clear(t.Context(), 1); err != nil {
+			t.Fatalf("Clear: %v", err)
+		}
+		if got := findCart(t, stores.Carts, 1); len(got) != 0 {
+			t.Fatalf("FindByUserId(1) after Clear returned %d items, want none", len(got))
+		}
+		assertCart(t, findCart(t, stores.Carts, 2), 3, 4)
+		if err := stores.Carts.Clear(t.Context(), 1); err != nil {
+			t.Fatalf("Clear of an empty cart: %v", err)
+		}
+	})
+}
+
+func mustSetItem(t *testing.T, repo port.CartRepository, userId, productId int, quantity uint) {
+	t.Helper()
+	if err := repo.SetItem(t.Context(), userId, productId, quantity); err != nil {
+		t.Fatalf("SetItem(%d, %d, %d): %v", userId, productId, quantity, err)
+	}
+}
+
+func findCart(t *testing.T, repo port.CartRepository, userId int) []entities.CartItem {
+	t.Helper()
+	items, err := repo.FindByUserId(t.Context(), userId)
+	if err != nil {
+		t.Fatalf("FindByUserId(%d): %v", userId, err)
+	}
+	return items
+}
+
+// assertCart checks items against product id, quantity pairs.
+func assertCart(t *testing.T, got []entities.CartItem, want ...uint) {
+	t.Helper()
+	if len(got)*2 != len(want) {
+		t.Fatalf("got %d cart items %+v, want %d", len(got), got, len(want)/2)
+	}
+	for i, item := range got {
+		if item.ProductId != want[2*i] || item.Quantity != want[2*i+1] {
+			t.Fatalf("cart item %d = %+v, want product %d x %d", i, item, want[2*i], want[2*i+1])
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package contract
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/product"
+)
+
+// InventoryStores pairs an InventoryOutbound with the ProductOutbound that
+// stores the products whose stock it keeps.
+type InventoryStores struct {
+	Products  port.ProductOutbound
+	Inventory port.InventoryOutbound
+}
+
+func RunInventoryOutbound(t *testing.T, newStores func(t *testing.T) InventoryStores) {
+	seed := func(t *testing.T) InventoryStores {
+		t.Helper()
+		stores := newStores(t)
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "keyboard", Price: thb(1290), Stock: 5})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "mouse", Price: thb(590), Stock: 1})
+		return stores
+	}
+
+	t.Run("ReserveTakesStock", func(t *testing.T) {
+		stores := seed(t)
+		if err := stores.Inventory.Reserve(t.Context(), stockItems(1, 2, 2, 1)); err != nil {
+			t.Fatalf("Reserve: %v", err)
+		}
+		assertStock(t, stores.Products, 1, 3)
+		assertStock(t, stores.Products, 2, 0)
+	})
+
+	t.Run("ReserveIsAllOrNothing", func(t *testing.T) {
+		stores := seed(t)
+		err := stores.Inventory.Reserve(t.Context(), stockItems(1, 2, 2, 2))
+		if !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Reserve beyond stock = %v, want ErrConflict", err)
+		}
+		assertStock(t, stores.Products, 1, 5)
+		assertStock(t, stores.Products, 2, 1)
+	})
+
+	t.Run("ReserveMissingProduct", func(t *testing.T) {
+		stores := seed(t)
+		if err := stores.Inventory.Reserve(t.Context(), stockItems(1, 1, 7, 1)); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("Reserve of product 7 = %v, want ErrNotFound", err)
+		}
+		assertStock(t, stores.Products, 1, 5)
+	})
+
+	t.Run("ReleaseReturnsStock", func(t *testing.T) {
+		stores := seed(t)
+		items := stockItems(1, 4, 2, 1)
+		if err := stores.Inventory.Reserve(t.Context(), items); err != nil {
+			t.Fatalf("Reserve: %v", err)
+		}
+		if err := stores.Inventory.Release(t.Context(), items); err != nil {
+			t.Fatalf("Release: %v", err)
+		}
+		assertStock(t, stores.Products, 1, 5)
+		assertStock(t, stores.Products, 2, 1)
+	})
+
+	t.Run("SetStock", func(t *testing.T) {
+		stores := seed(t)
+		if err := stores.Inventory.SetStock(t.Context(), 2, 40); err != nil {
+			t.Fatalf("SetStock: %v", err)
+		}
+		assertStock(t, stores.Products, 2, 40)
+		assertStock(t, stores.Products, 1, 5)
+	})
+
+	t.Run("SetStockMissing", func(t *testing.T) {
+		stores := seed(t)
+		if err := stores.Inventory.SetStock(t.Context(), 7, 1); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("SetStock(7) = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("ConcurrentReservationsNeverOversell", func(t *testing.T) {
+		stores := seed(t)
+		const buyers = 20
+		var wg sync.WaitGroup
+		errs := make([]error, buyers)
+		for i := range buyers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = stores.Inventory.Reserve(t.Context(), stockItems(1, 1))
+			}()
+		}
+		wg.Wait()
+
+		var reserved int
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				reserved++
+			case !errors.Is(err, entities.ErrConflict):
+				t.Fatalf("Reserve: %v", err)
+			}
+		}
+		if reserved != 5 {
+			t.Fatalf("%d concurrent reservations succeeded, want 5", reserved)
+		}
+		assertStock(t, stores.Products, 1, 0)
+	})
+}
+
+// stockItems builds order items from product id, quantity pairs.
+func stockItems(pairs ...uint) []entities.OrderItem {
+	var items []entities.OrderItem
+	for i := 0; i+1 < len(pairs); i += 2 {
+		items = append(items, entities.OrderItem{ProductId: pairs[i], Quantity: pairs[i+1]})
+	}
+	return items
+}
+
+func assertStock(t *testing.T, repo port.ProductOutbound, productId int, want uint) {
+	t.Helper()
+	product, err := repo.FindById(t.Context(), productId)
+	if err != nil {
+		t.Fatalf("FindById(%d): %v", productId, err)
+	}
+	if product.Stock != want {
+		t.Fatalf("product %d has stock %d, want %d", productId, product.Stock, want)
+	}
+}
@@ -0,0 +1,227 @@
+package contract
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/order"
+	productPort "github.com/wittawat/go-hex/core/port/product"
+	userPort "github.com/wittawat/go-hex/core/port/user"
+)
+
+// OrderStores is what an OrderRepository needs around it: orders reference
+// users and products, so the suite seeds those through the sibling ports.
+type OrderStores struct {
+	Orders   port.OrderRepository
+	Users    userPort.UserOutbound
+	Products productPort.ProductOutbound
+}
+
+func RunOrderRepository(t *testing.T, newStores func(t *testing.T) OrderStores) {
+	seed := func(t *testing.T) OrderStores {
+		t.Helper()
+		stores := newStores(t)
+		mustSaveUser(t, stores.Users, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		mustSaveUser(t, stores.Users, entities.User{Username: "bob", Email: "bob@example.com", Password: "secret"})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "keyboard", Price: thb(1290), Detail: "mechanical"})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "mouse", Price: thb(590), Detail: "wireless"})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "monitor", Price: thb(5900), Detail: "27 inch"})
+		return stores
+	}
+	keyboard := entities.OrderItem{ProductId: 1, Title: "keyboard", Quantity: 1, UnitPrice: thb(1290)}
+	mice := entities.OrderItem{ProductId: 2, Title: "mouse", Quantity: 3, UnitPrice: thb(590)}
+	monitor := entities.OrderItem{ProductId: 3, Title: "monitor", Quantity: 1, UnitPrice: thb(5900)}
+
+	t.Run("SaveThenFindById", func(t *testing.T) {
+		stores := seed(t)
+		mustSaveOrder(t, stores.Orders, newOrder(1, keyboard))
+		want := mustSaveOrder(t, stores.Orders, newOrder(2, mice, monitor))
+		if len(want.Items) != 2 || want.Items[0].Id == 0 || want.Items[0].Id == want.Items[1].Id {
+			t.Fatalf("Save set items %+v, want distinct item ids", want.Items)
+		}
+
+		got, err := stores.Orders.FindById(t.Context(), want.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", want.Id, err)
+		}
+		assertOrder(t, *got, want)
+	})
+
+	t.Run("FindByIdNotFound", func(t *testing.T) {
+		stores := seed(t)
+		if _, err := stores.Orders.FindById(t.Context(), 42); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById(42) returned %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("FindByUserIdReturnsOrdersInSequence", func(t *testing.T) {
+		stores := seed(t)
+		first := mustSaveOrder(t, stores.Orders, newOrder(1, monitor))
+		mustSaveOrder(t, stores.Orders, newOrder(2, mice))
+		second := mustSaveOrder(t, stores.Orders, newOrder(1, keyboard, mice))
+
+		got, err := stores.Orders.FindByUserId(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("FindByUserId(1): %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("FindByUserId(1) returned %d orders, want 2", len(got))
+		}
+		assertOrder(t, got[0], first)
+		assertOrder(t, got[1], second)
+	})
+
+	t.Run("FindByUserIdWithoutOrders", func(t *testing.T) {
+		stores := seed(t)
+		got, err := stores.Orders.FindByUserId(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("FindByUserId(1): %v", err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("FindByUserId(1) returned %d orders, want none", len(got))
+		}
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		stores := seed(t)
+		order := mustSaveOrder(t, stores.Orders, newOrder(1, keyboard))
+		other := mustSaveOrder(t, stores.Orders, newOrder(1, mice))
+
+		if err := stores.Orders.UpdateStatus(t.Context(), order.Id, entities.OrderPending, entities.OrderPaid); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+		got, err := stores.Orders.FindById(t.Context(), order.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", order.Id, err)
+		}
+		if got.UpdatedAt.Before(order.UpdatedAt) {
+			t.Fatalf("UpdateStatus moved updated_at backwards: %v", got.UpdatedAt)
+		}
+		want := order
+		want.Status, want.UpdatedAt = entities.OrderPaid, got.UpdatedAt
+		assertOrder(t, *got, want)
+
+		got, err = stores.Orders.FindById(t.Context(), other.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", other.Id, err)
+		}
+		assertOrder(t, *got, other)
+	})
+
+	t.Run("UpdateStatusMissing", func(t *testing.T) {
+		stores := seed(t)
+		if err := stores.Orders.UpdateStatus(t.Context(), 7, entities.OrderPending, entities.OrderPaid); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("UpdateStatus(7) = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("UpdateStatusFromStaleStatus", func(t *testing.T) {
+		stores := seed(t)
+		order := mustSaveOrder(t, stores.Orders, newOrder(1, keyboard))
+		if err := stores.Orders.UpdateStatus(t.Context(), order.Id, entities.OrderPending, entities.OrderPaid); err != nil {
+			t.Fatalf("UpdateStatus: %v", err)
+		}
+
+		err := stores.Orders.UpdateStatus(t.Context(), order.Id, entities.OrderPending, entities.OrderCancelled)
+		if !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("UpdateStatus from a stale status = %v, want ErrConflict", err)
+		}
+		got, err := stores.Orders.FindById(t.Context(), order.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", order.Id, err)
+		}
+		if got.Status != entities.OrderPaid {
+			t.Fatalf("status after a rejected update = %s, want %s", got.Status, entities.OrderPaid)
+		}
+	})
+
+	t.Run("DeleteOne", func(t *testing.T) {
+		stores := seed(t)
+		order := mustSaveOrder(t, stores.Orders, newOrder(1, keyboard, mice))
+		other := mustSaveOrder(t, stores.Orders, newOrder(1, monitor))
+
+		if err := stores.Orders.DeleteOne(t.Context(), order.Id); err != nil {
+			t.Fatalf("DeleteOne(%d): %v", order.Id, err)
+		}
+		if _, err := stores.Orders.FindById(t.Context(), order.Id); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById(%d) of a deleted order returned %v, want ErrNotFound", order.Id, err)
+		}
+		got, err := stores.Orders.FindByUserId(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("FindByUserId(1): %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("FindByUserId(1) after delete returned %d orders, want 1", len(got))
+		}
+		assertOrder(t, got[0], other)
+	})
+
+	t.Run("DeleteOneMissing", func(t *testing.T) {
+		stores := seed(t)
+		mustSaveOrder(t, stores.Orders, newOrder(1, keyboard))
+		if err := stores.Orders.DeleteOne(t.Context(), 42); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("DeleteOne(42) = %v, want ErrNotFound", err)
+		}
+		if _, err := stores.Orders.FindById(t.Context(), 1); err != nil {
+			t.Fatalf("DeleteOne on a missing id removed another order: %v", err)
+		}
+	})
+
+	t.Run("DeleteOneOfAnOrderedProduct", func(t *testing.T) {
+		stores := seed(t)
+		order := mustSaveOrder(t, stores.Orders, newOrder(1, keyboard, mice))
+
+		if err := stores.Products.DeleteOne(t.Context(), 1); err != nil {
+			t.Fatalf("DeleteOne of an ordered product: %v", err)
+		}
+		if _, err := stores.Products.FindById(t.Context(), 1); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById of a deleted product returned %v, want ErrNotFound", err)
+		}
+		products, _ := findProducts(t, stores.Products, entities.ProductQuery{})
+		assertTitles(t, products, "mouse", "monitor")
+		if err := stores.Products.DeleteOne(t.Context(), 1); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("second DeleteOne of a product = %v, want ErrNotFound", err)
+		}
+		got, err := stores.Orders.FindById(t.Context(), order.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d) after its product was deleted: %v", order.Id, err)
+		}
+		assertOrder(t, *got, order)
+	})
+}
+
+// newOrder builds a pending order with its total, as OrderService would;
+// items must share a currency.
+func newOrder(userId uint, items ...entities.OrderItem) entities.Order {
+	order := entities.Order{UserId: userId, Status: entities.OrderPending, Items: items}
+	order.ComputeTotal()
+	return order
+}
+
+// mustSaveOrder saves order and returns it with the generated ids and timestamps.
+func mustSaveOrder(t *testing.T, repo port.OrderRepository, order entities.Order) entities.Order {
+	t.Helper()
+	order.Items = append([]entities.OrderItem(nil), order.Items...)
+	id, err := repo.Save(t.Context(), &order)
+	if err != nil {
+		t.Fatalf("Save(%+v): %v", order, err)
+	}
+	if id <= 0 || order.Id != id || order.CreatedAt.IsZero() {
+		t.Fatalf("Save returned id %d and set %+v, want the same positive id and timestamps", id, order)
+	}
+	return order
+}
+
+func assertOrder(t *testing.T, got, want entities.Order) {
+	t.Helper()
+	if got.Id != want.Id || got.UserId != want.UserId || got.Status != want.Status || got.Total != want.Total ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) || len(got.Items) != len(want.Items) {
+		t.Fatalf("got order %+v, want %+v", got, want)
+	}
+	for i := range want.Items {
+		if got.Items[i] != want.Items[i] {
+			t.Fatalf("got item %d %+v, want %+v", i, got.Items[i], want.Items[i])
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package contract
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+	orderPort "github.com/wittawat/go-hex/core/port/order"
+	port "github.com/wittawat/go-hex/core/port/payment"
+	productPort "github.com/wittawat/go-hex/core/port/product"
+	userPort "github.com/wittawat/go-hex/core/port/user"
+)
+
+// PaymentStores is what a PaymentRepository needs around it: payments belong
+// to orders, which the suite seeds through the sibling ports.
+type PaymentStores struct {
+	Payments port.PaymentRepository
+	Orders   orderPort.OrderRepository
+	Users    userPort.UserOutbound
+	Products productPort.ProductOutbound
+}
+
+func RunPaymentRepository(t *testing.T, newStores func(t *testing.T) PaymentStores) {
+	seed := func(t *testing.T) PaymentStores {
+		t.Helper()
+		stores := newStores(t)
+		mustSaveUser(t, stores.Users, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "keyboard", Price: thb(1290)})
+		keyboard := entities.OrderItem{ProductId: 1, Title: "keyboard", Quantity: 1, UnitPrice: thb(1290)}
+		mustSaveOrder(t, stores.Orders, newOrder(1, keyboard))
+		mustSaveOrder(t, stores.Orders, newOrder(1, keyboard))
+		return stores
+	}
+
+	t.Run("SaveThenFindByReference", func(t *testing.T) {
+		stores := seed(t)
+		want := mustSavePayment(t, stores.Payments, newPayment(1, "pay_a"))
+		if want.CreatedAt.IsZero() || !want.UpdatedAt.Equal(want.CreatedAt) {
+			t.Fatalf("Save set timestamps %v / %v, want equal non-zero times", want.CreatedAt, want.UpdatedAt)
+		}
+
+		got, err := stores.Payments.FindByReference(t.Context(), "pay_a")
+		if err != nil {
+			t.Fatalf("FindByReference: %v", err)
+		}
+		assertPayment(t, *got, want)
+	})
+
+	t.Run("SaveDuplicateReference", func(t *testing.T) {
+		stores := seed(t)
+		mustSavePayment(t, stores.Payments, newPayment(1, "pay_a"))
+		payment := newPayment(2, "pay_a")
+		if _, err := stores.Payments.Save(t.Context(), &payment); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Save with a used reference = %v, want ErrConflict", err)
+		}
+	})
+
+	t.Run("SaveSecondPendingPaymentForAnOrder", func(t *testing.T) {
+		stores := seed(t)
+		first := mustSavePayment(t, stores.Payments, newPayment(1, "pay_a"))
+		mustSavePayment(t, stores.Payments, newPayment(2, "pay_b"))
+
+		payment := newPayment(1, "pay_c")
+		if _, err := stores.Payments.Save(t.Context(), &payment); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Save of a second pending payment = %v, want ErrConflict", err)
+		}
+		// only pending payments are limited
+		declined := newPayment(1, "pay_d")
+		declined.Status = entities.PaymentDeclined
+		mustSavePayment(t, stores.Payments, declined)
+
+		mustSettle(t, stores.Payments, first, entities.PaymentDeclined)
+		mustSavePayment(t, stores.Payments, newPayment(1, "pay_c"))
+	})
+
+	t.Run("FindByReferenceNotFound", func(t *testing.T) {
+		if _, err := seed(t).Payments.FindByReference(t.Context(), "pay_x"); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindByReference(pay_x) = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("FindByOrderIdReturnsPaymentsInSequence", func(t *testing.T) {
+		stores := seed(t)
+		first := mustSavePayment(t, stores.Payments, newPayment(1, "pay_a"))
+		mustSavePayment(t, stores.Payments, newPayment(2, "pay_b"))
+		first = mustSettle(t, stores.Payments, first, entities.PaymentDeclined)
+		second := mustSavePayment(t, stores.Payments, newPayment(1, "pay_c"))
+
+		got, err := stores.Payments.FindByOrderId(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("FindByOrderId(1): %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("FindByOrderId(1) returned %d payments, want 2", len(got))
+		}
+		assertPayment(t, got[0], first)
+		assertPayment(t, got[1], second)
+	})
+
+	t.Run("Settle", func(t *testing.T) {
+		stores := seed(t)
+		payment := mustSavePayment(t, stores.Payments, newPayment(1, "pay_a"))
+
+		result := entities.PaymentResult{Reference: "pay_a", Status: entities.PaymentDeclined, Reason: "card declined"}
+		if err := stores.Payments.Settle(t.Context(), payment.Id, result); err != nil {
+			t.Fatalf("Settle: %v", err)
+		}
+		got, err := stores.Payments.FindByReference(t.Context(), "pay_a")
+		if err != nil {
+			t.Fatalf("FindByReference: %v", err)
+		}
+		if got.UpdatedAt.Before(payment.UpdatedAt) {
+			t.Fatalf("Settle moved updated_at backwards: %v", got.UpdatedAt)
+		}
+		want := payment
+		want.Status, want.Reason, want.UpdatedAt = entities.PaymentDeclined, "card declined", got.UpdatedAt
+		assertPayment(t, *got, want)
+	})
+
+	t.Run("SettleTwice", func(t *testing.T) {
+		stores := seed(t)
+		payment := mustSavePayment(t, stores.Payments, newPayment(1, "pay_a"))
+		approve := entities.PaymentResult{Reference: "pay_a", Status: entities.PaymentAuthorized}
+		if err := stores.Payments.Settle(t.Context(), payment.Id, approve); err != nil {
+			t.Fatalf("Settle: %v", err)
+		}
+
+		decline := entities.PaymentResult{Reference: "pay_a", Status: entities.PaymentDeclined}
+		if err := stores.Payments.Settle(t.Context(), payment.Id, decline); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Settle of a settled payment = %v, want ErrConflict", err)
+		}
+		got, err := stores.Payments.FindByReference(t.Context(), "pay_a")
+		if err != nil {
+			t.Fatalf("FindByReference: %v", err)
+		}
+		if got.Status != entities.PaymentAuthorized {
+			t.Fatalf("status after a rejected Settle = %s, want %s", got.Status, entities.PaymentAuthorized)
+		}
+	})
+
+	t.Run("SettleMissing", func(t *testing.T) {
+		result := entities.PaymentResult{Reference: "pay_x", Status: entities.PaymentAuthorized}
+		if err := seed(t).Payments.Settle(t.Context(), 7, result); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("Settle(7) = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("MarkRefundDue", func(t *testing.T) {
+		stores := seed(t)
+		payment := mustSettle(t, stores.Payments, mustSavePayment(t, stores.Payments, newPayment(1, "pay_a")), entities.PaymentAuthorized)
+
+		for range 2 {
+			if err := stores.Payments.MarkRefundDue(t.Context(), payment.Id); err != nil {
+				t.Fatalf("MarkRefundDue: %v", err)
+			}
+		}
+		got, err := stores.Payments.FindByReference(t.Context(), "pay_a")
+		if err != nil {
+			t.Fatalf("FindByReference: %v", err)
+		}
+		if !got.RefundDue || got.Status != entities.PaymentAuthorized {
+			t.Fatalf("payment after MarkRefundDue = %+v, want an authorized payment with a refund due", got)
+		}
+		orders, err := stores.Payments.FindByOrderId(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("FindByOrderId(1): %v", err)
+		}
+		if len(orders) != 1 || !orders[0].RefundDue {
+			t.Fatalf("FindByOrderId(1) = %+v, want the payment with a refund due", orders)
+		}
+	})
+
+	t.Run("MarkRefundDueOfUnauthorizedPayment", func(t *testing.T) {
+		stores := seed(t)
+		pending := mustSavePayment(t, stores.Payments, newPayment(1, "pay_a"))
+		declined := mustSettle(t, stores.Payments, mustSavePayment(t, stores.Payments, newPayment(2, "pay_b")), entities.PaymentDeclined)
+
+		for _, payment := range []entities.Payment{pending, declined} {
+			if err := stores.Payments.MarkRefundDue(t.Context(), payment.Id); !errors.Is(err, entities.ErrConflict) {
+				t.Fatalf("MarkRefundDue of a %s payment = %v, want ErrConflict", payment.Status, err)
+			}
+		}
+		if err := stores.Payments.MarkRefundDue(t.Context(), 7); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("MarkRefundDue(7) = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+// mustSettle records status on payment and returns it as now stored.
+func mustSettle(t *testing.T, repo port.PaymentRepository, payment entities.Payment, status entities.PaymentStatus) entities.Payment {
+	t.Helper()
+	if err := repo.Settle(t.Context(), payment.Id, entities.PaymentResult{Reference: payment.Reference, Status: status}); err != nil {
+		t.Fatalf("Settle(%d): %v", payment.Id, err)
+	}
+	got, err := repo.FindByReference(t.Context(), payment.Reference)
+	if err != nil {
+		t.Fatalf("FindByReference(%s): %v", payment.Reference, err)
+	}
+	return *got
+}
+
+func newPayment(orderId uint, reference string) entities.Payment {
+	return entities.Payment{OrderId: orderId, Reference: reference, Amount: thb(1290), Status: entities.PaymentPending}
+}
+
+func mustSavePayment(t *testing.T, repo port.PaymentRepository, payment entities.Payment) entities.Payment {
+	t.Helper()
+	id, err := repo.Save(t.Context(), &payment)
+	if err != nil {
+		t.Fatalf("Save(%+v): %v", payment, err)
+	}
+	if id <= 0 || payment.Id != id {
+		t.Fatalf("Save returned id %d and set payment.Id %d, want the same positive id", id, payment.Id)
+	}
+	return payment
+}
+
+func assertPayment(t *testing.T, got, want entities.Payment) {
+	t.Helper()
+	if got.Id != want.Id || got.OrderId != want.OrderId || got.Reference != want.Reference || got.Amount != want.Amount ||
+		got.Status != want.Status || got.Reason != want.Reason || got.RefundDue != want.RefundDue ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Fatalf("got payment %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,249 @@
+package contract
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/product"
+)
+
+func RunProductOutbound(t *testing.T, newRepo func(t *testing.T) port.ProductOutbound) {
+	t.Run("SaveThenFindById", func(t *testing.T) {
+		repo := newRepo(t)
+		want := mustSaveProduct(t, repo, entities.Product{Title: "keyboard", Price: thb(1290), Detail: "mechanical", Stock: 12})
+		if want.Id != 1 {
+			t.Fatalf("first Save returned id %d, want 1", want.Id)
+		}
+		if want.CreatedAt.IsZero() || !want.UpdatedAt.Equal(want.CreatedAt) {
+			t.Fatalf("Save set timestamps %v / %v, want equal non-zero times", want.CreatedAt, want.UpdatedAt)
+		}
+
+		got, err := repo.FindById(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("FindById(1): %v", err)
+		}
+		assertProduct(t, *got, want)
+	})
+
+	t.Run("FindReturnsProductsInInsertOrder", func(t *testing.T) {
+		repo := newRepo(t)
+		want := []entities.Product{
+			mustSaveProduct(t, repo, entities.Product{Title: "keyboard", Price: thb(1290), Detail: "mechanical"}),
+			mustSaveProduct(t, repo, entities.Product{Title: "mouse", Price: thb(590), Detail: "wireless"}),
+			mustSaveProduct(t, repo, entities.Product{Title: "monitor", Price: thb(5900), Detail: "27 inch"}),
+		}
+
+		got, page := findProducts(t, repo, entities.ProductQuery{})
+		if page.Total != len(want) || page.NextCursor != "" {
+			t.Fatalf("Find page = %+v, want total %d and no next cursor", page, len(want))
+		}
+		if len(got) != len(want) {
+			t.Fatalf("Find returned %d products, want %d", len(got), len(want))
+		}
+		for i := range want {
+			assertProduct(t, got[i], want[i])
+		}
+	})
+
+	t.Run("FindOnEmptyStore", func(t *testing.T) {
+		got, _ := findProducts(t, newRepo(t), entities.ProductQuery{})
+		if len(got) != 0 {
+			t.Fatalf("Find returned %d products, want none", len(got))
+		}
+	})
+
+	t.Run("FindFollowsCursorsThroughAllPages", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, title := range []string{"a", "b", "c", "d", "e"} {
+			mustSaveProduct(t, repo, entities.Product{Title: title, Price: thb(100)})
+		}
+
+		query := entities.ProductQuery{PageRequest: entities.PageRequest{Limit: 2}}
+		var titles []string
+		for pages := 1; ; pages++ {
+			got, page := findProducts(t, repo, query)
+			if page.Total != 5 || page.Limit != 2 {
+				t.Fatalf("page %d = %+v, want total 5 and limit 2", pages, page)
+			}
+			for _, product := range got {
+				titles = append(titles, product.Title)
+			}
+			if page.NextCursor == "" {
+				if pages != 3 {
+					t.Fatalf("got %d pages, want 3", pages)
+				}
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+		if strings.Join(titles, "") != "abcde" {
+			t.Fatalf("paged titles = %v, want a b c d e", titles)
+		}
+	})
+
+	t.Run("FindSortsByPriceDescending", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveProduct(t, repo, entities.Product{Title: "mouse", Price: thb(590)})
+		mustSaveProduct(t, repo, entities.Product{Title: "monitor", Price: thb(5900)})
+		mustSaveProduct(t, repo, entities.Product{Title: "cable", Price: thb(590)})
+		mustSaveProduct(t, repo, entities.Product{Title: "keyboard", Price: thb(1290)})
+
+		got, _ := findProducts(t, repo, entities.ProductQuery{PageRequest: entities.PageRequest{Sort: "price", Direction: entities.SortDesc}})
+		// equal prices fall back to id, in the same direction
+		assertTitles(t, got, "monitor", "keyboard", "cable", "mouse")
+	})
+
+	t.Run("FindFiltersByPriceAndTitle", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveProduct(t, repo, entities.Product{Title: "Mechanical Keyboard", Price: thb(1290)})
+		mustSaveProduct(t, repo, entities.Product{Title: "travel mouse", Price: entities.NewMoney(400, entities.USD)})
+		mustSaveProduct(t, repo, entities.Product{Title: "keyboard cover", Price: thb(190)})
+		mustSaveProduct(t, repo, entities.Product{Title: "mouse", Price: thb(590)})
+		mustSaveProduct(t, repo, entities.Product{Title: "100% cotton pad", Price: thb(250)})
+		mustSaveProduct(t, repo, entities.Product{Title: "1000 dpi mouse", Price: thb(250)})
+
+		got, page := findProducts(t, repo, entities.ProductQuery{TitleContains: "KEYBOARD"})
+		assertTitles(t, got, "Mechanical Keyboard", "keyboard cover")
+		if page.Total != 2 {
+			t.Fatalf("filtered total = %d, want 2", page.Total)
+		}
+
+		// price bounds apply within one currency
+		got, _ = findProducts(t, repo, entities.ProductQuery{Currency: entities.THB, MinPrice: 200, MaxPrice: 600})
+		assertTitles(t, got, "mouse", "100% cotton pad", "1000 dpi mouse")
+
+		got, _ = findProducts(t, repo, entities.ProductQuery{Currency: entities.USD})
+		assertTitles(t, got, "travel mouse")
+
+		// LIKE wildcards in the filter match literally
+		got, _ = findProducts(t, repo, entities.ProductQuery{TitleContains: "100%"})
+		assertTitles(t, got, "100% cotton pad")
+	})
+
+	t.Run("FindByIdNotFound", func(t *testing.T) {
+		if _, err := newRepo(t).FindById(t.Context(), 42); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById(42) on an empty store returned %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("UpdateOne", func(t *testing.T) {
+		repo := newRepo(t)
+		keyboard := mustSaveProduct(t, repo, entities.Product{Title: "keyboard", Price: thb(1290), Detail: "mechanical", Stock: 4})
+		mouse := mustSaveProduct(t, repo, entities.Product{Title: "mouse", Price: thb(590), Detail: "wireless"})
+
+		update := entities.Product{Title: "keyboard v2", Price: thb(1490), Detail: "hot-swap", Stock: 99}
+		if err := repo.UpdateOne(t.Context(), &update, keyboard.Id); err != nil {
+			t.Fatalf("UpdateOne: %v", err)
+		}
+
+		got, err := repo.FindById(t.Context(), keyboard.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", keyboard.Id, err)
+		}
+		if !got.CreatedAt.Equal(keyboard.CreatedAt) || got.UpdatedAt.Before(keyboard.UpdatedAt) {
+			t.Fatalf("UpdateOne changed created_at or moved updated_at backwards: %+v", got)
+		}
+		// Stock only changes through the inventory port.
+		want := update
+		want.Id, want.Stock, want.CreatedAt, want.UpdatedAt = keyboard.Id, keyboard.Stock, keyboard.CreatedAt, got.UpdatedAt
+		assertProduct(t, *got, want)
+
+		other, err := repo.FindById(t.Context(), mouse.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", mouse.Id, err)
+		}
+		assertProduct(t, *other, mouse)
+	})
+
+	t.Run("UpdateOneMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.UpdateOne(t.Context(), &entities.Product{Title: "ghost", Price: thb(1), Detail: "none"}, 7); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("UpdateOne(7) = %v, want ErrNotFound", err)
+		}
+		if _, err := repo.FindById(t.Context(), 7); err == nil {
+			t.Fatal("UpdateOne on a missing id created a product")
+		}
+	})
+
+	t.Run("DeleteOne", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveProduct(t, repo, entities.Product{Title: "keyboard", Price: thb(1290), Detail: "mechanical"})
+		mustSaveProduct(t, repo, entities.Product{Title: "mouse", Price: thb(590), Detail: "wireless"})
+
+		if err := repo.DeleteOne(t.Context(), 1); err != nil {
+			t.Fatalf("DeleteOne(1): %v", err)
+		}
+		if _, err := repo.FindById(t.Context(), 1); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById(1) of a deleted product returned %v, want ErrNotFound", err)
+		}
+
+		products, _ := findProducts(t, repo, entities.ProductQuery{})
+		if len(products) != 1 || products[0].Title != "mouse" {
+			t.Fatalf("Find after delete = %+v, want only mouse", products)
+		}
+	})
+
+	t.Run("DeleteOneMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveProduct(t, repo, entities.Product{Title: "keyboard", Price: thb(1290), Detail: "mechanical"})
+		if err := repo.DeleteOne(t.Context(), 42); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("DeleteOne(42) = %v, want ErrNotFound", err)
+		}
+		if _, err := repo.FindById(t.Context(), 1); err != nil {
+			t.Fatalf("DeleteOne on a missing id removed another product: %v", err)
+		}
+	})
+}
+
+// thb returns amount minor units of Thai baht.
+func thb(amount int64) entities.Money {
+	return entities.NewMoney(amount, entities.THB)
+}
+
+// mustSaveProduct saves product and returns it with the generated id and timestamps.
+func mustSaveProduct(t *testing.T, repo port.ProductOutbound, product entities.Product) entities.Product {
+	t.Helper()
+	id, err := repo.Save(t.Context(), &product)
+	if err != nil {
+		t.Fatalf("Save(%+v): %v", product, err)
+	}
+	if id <= 0 || product.Id != id {
+		t.Fatalf("Save returned id %d and set product.Id %d, want the same positive id", id, product.Id)
+	}
+	return product
+}
+
+// findProducts normalizes query and returns the page it selects.
+func findProducts(t *testing.T, repo port.ProductOutbound, query entities.ProductQuery) ([]entities.Product, entities.PageInfo) {
+	t.Helper()
+	if err := query.Normalize(); err != nil {
+		t.Fatalf("Normalize(%+v): %v", query, err)
+	}
+	products, page, err := repo.Find(t.Context(), query)
+	if err != nil {
+		t.Fatalf("Find(%+v): %v", query, err)
+	}
+	return products, page
+}
+
+func assertTitles(t *testing.T, got []entities.Product, want ...string) {
+	t.Helper()
+	titles := make([]string, 0, len(got))
+	for _, product := range got {
+		titles = append(titles, product.Title)
+	}
+	if !slices.Equal(titles, want) {
+		t.Fatalf("got titles %q, want %q", titles, want)
+	}
+}
+
+func assertProduct(t *testing.T, got, want entities.Product) {
+	t.Helper()
+	if got.Id != want.Id || got.Title != want.Title || got.Price != want.Price || got.Detail != want.Detail || got.Stock != want.Stock ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Fatalf("got product %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+	productPort "github.com/wittawat/go-hex/core/port/product"
+	port "github.com/wittawat/go-hex/core/port/uow"
+)
+
+// UnitOfWorkStores pairs a UnitOfWork with repositories whose writes it covers.
+type UnitOfWorkStores struct {
+	Uow       port.UnitOfWork
+	Products  productPort.ProductOutbound
+	Inventory productPort.InventoryOutbound
+}
+
+func RunUnitOfWork(t *testing.T, newStores func(t *testing.T) UnitOfWorkStores) {
+	errAbort := errors.New("abort")
+
+	t.Run("CommitKeepsWrites", func(t *testing.T) {
+		stores := newStores(t)
+		err := stores.Uow.Do(t.Context(), func(ctx context.Context) error {
+			product := entities.Product{Title: "keyboard", Price: thb(1290)}
+			if _, err := stores.Products.Save(ctx, &product); err != nil {
+				return err
+			}
+			if err := stores.Inventory.SetStock(ctx, product.Id, 3); err != nil {
+				return err
+			}
+			// Reads inside the unit see its own writes.
+			return stores.Inventory.Reserve(ctx, stockItems(uint(product.Id), 1))
+		})
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		assertStock(t, stores.Products, 1, 2)
+	})
+
+	t.Run("ErrorRollsBackEveryWrite", func(t *testing.T) {
+		stores := newStores(t)
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "keyboard", Price: thb(1290), Stock: 5})
+
+		err := stores.Uow.Do(t.Context(), func(ctx context.Context) error {
+			if err := stores.Inventory.Reserve(ctx, stockItems(1, 2)); err != nil {
+				return err
+			}
+			if _, err := stores.Products.Save(ctx, &entities.Product{Title: "mouse", Price: thb(590)}); err != nil {
+				return err
+			}
+			if err := stores.Products.DeleteOne(ctx, 1); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("Do = %v, want the error fn returned", err)
+		}
+		assertStock(t, stores.Products, 1, 5)
+		got, _ := findProducts(t, stores.Products, entities.ProductQuery{})
+		assertTitles(t, got, "keyboard")
+	})
+
+	t.Run("NestedDoJoinsTheOuterUnit", func(t *testing.T) {
+		stores := newStores(t)
+		mustSaveProduct(t, stores.Products, entities.Product{Title: "keyboard", Price: thb(1290), Stock: 5})
+
+		err := stores.Uow.Do(t.Context(), func(ctx context.Context) error {
+			err := stores.Uow.Do(ctx, func(ctx context.Context) error {
+				return stores.Inventory.Reserve(ctx, stockItems(1, 5))
+			})
+			if err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("Do = %v, want the error fn returned", err)
+		}
+		assertStock(t, stores.Products, 1, 5)
+	})
+}
@@ -0,0 +1,227 @@
+// Package contract holds conformance suites for the outbound ports. An adapter
+// proves it behaves like the others by running the suite from its own tests:
+//
+//	func TestSqlUserRepository(t *testing.T) {
+//		contract.RunUserOutbound(t, func(t *testing.T) port.UserOutbound {
+//			return adapter.NewSqlUserRepository(dbtest.SQLite(t))
+//		})
+//	}
+//
+// Every factory call must return a repository backed by an empty store, so
+// that generated ids start from 1.
+package contract
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/user"
+)
+
+func RunUserOutbound(t *testing.T, newRepo func(t *testing.T) port.UserOutbound) {
+	t.Run("SaveThenFindById", func(t *testing.T) {
+		repo := newRepo(t)
+		want := mustSaveUser(t, repo, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		if want.Id != 1 {
+			t.Fatalf("first Save returned id %d, want 1", want.Id)
+		}
+		if want.CreatedAt.IsZero() || !want.UpdatedAt.Equal(want.CreatedAt) {
+			t.Fatalf("Save set timestamps %v / %v, want equal non-zero times", want.CreatedAt, want.UpdatedAt)
+		}
+
+		got, err := repo.FindById(t.Context(), 1)
+		if err != nil {
+			t.Fatalf("FindById(1): %v", err)
+		}
+		assertUser(t, *got, want)
+	})
+
+	t.Run("FindReturnsUsersInInsertOrder", func(t *testing.T) {
+		repo := newRepo(t)
+		want := []entities.User{
+			mustSaveUser(t, repo, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"}),
+			mustSaveUser(t, repo, entities.User{Username: "bob", Email: "bob@example.com", Password: "secret"}),
+			mustSaveUser(t, repo, entities.User{Username: "carol", Email: "carol@example.com", Password: "secret"}),
+		}
+
+		got, page := findUsers(t, repo, entities.UserQuery{})
+		if page.Total != len(want) || page.NextCursor != "" {
+			t.Fatalf("Find page = %+v, want total %d and no next cursor", page, len(want))
+		}
+		if len(got) != len(want) {
+			t.Fatalf("Find returned %d users, want %d", len(got), len(want))
+		}
+		for i := range want {
+			assertUser(t, got[i], want[i])
+		}
+	})
+
+	t.Run("FindOnEmptyStore", func(t *testing.T) {
+		got, _ := findUsers(t, newRepo(t), entities.UserQuery{})
+		if len(got) != 0 {
+			t.Fatalf("Find returned %d users, want none", len(got))
+		}
+	})
+
+	t.Run("FindFiltersAndSorts", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveUser(t, repo, entities.User{Username: "alice", Email: "alice@shop.example", Password: "secret", Role: entities.RoleStaff})
+		mustSaveUser(t, repo, entities.User{Username: "bob", Email: "bob@example.com", Password: "secret", Role: entities.RoleCustomer})
+		mustSaveUser(t, repo, entities.User{Username: "carol", Email: "carol@shop.example", Password: "secret", Role: entities.RoleCustomer})
+		mustSaveUser(t, repo, entities.User{Username: "shopkeeper", Email: "dave@example.com", Password: "secret", Role: entities.RoleAdmin})
+
+		got, page := findUsers(t, repo, entities.UserQuery{Role: entities.RoleCustomer})
+		assertUsernames(t, got, "bob", "carol")
+		if page.Total != 2 {
+			t.Fatalf("filtered total = %d, want 2", page.Total)
+		}
+
+		// the search matches usernames and emails
+		got, _ = findUsers(t, repo, entities.UserQuery{Search: "SHOP", PageRequest: entities.PageRequest{Sort: "username", Direction: entities.SortDesc}})
+		assertUsernames(t, got, "shopkeeper", "carol", "alice")
+	})
+
+	t.Run("FindByIdNotFound", func(t *testing.T) {
+		if _, err := newRepo(t).FindById(t.Context(), 42); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById(42) on an empty store returned %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("FindByEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveUser(t, repo, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		want := mustSaveUser(t, repo, entities.User{Username: "bob", Email: "bob@example.com", Password: "secret"})
+
+		got, err := repo.FindByEmail(t.Context(), "bob@example.com")
+		if err != nil {
+			t.Fatalf("FindByEmail: %v", err)
+		}
+		assertUser(t, *got, want)
+
+		if _, err := repo.FindByEmail(t.Context(), "nobody@example.com"); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindByEmail of an unknown email returned %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("SaveRejectsDuplicateEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveUser(t, repo, entities.User{Username: "alice", Email: "same@example.com", Password: "secret"})
+		if _, err := repo.Save(t.Context(), &entities.User{Username: "bob", Email: "same@example.com", Password: "secret"}); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Save of a duplicate email = %v, want ErrConflict", err)
+		}
+	})
+
+	t.Run("UpdateOne", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := mustSaveUser(t, repo, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		bob := mustSaveUser(t, repo, entities.User{Username: "bob", Email: "bob@example.com", Password: "secret"})
+
+		update := entities.User{Username: "alice2", Email: "alice2@example.com", Password: "changed"}
+		if err := repo.UpdateOne(t.Context(), &update, alice.Id); err != nil {
+			t.Fatalf("UpdateOne: %v", err)
+		}
+
+		got, err := repo.FindById(t.Context(), alice.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", alice.Id, err)
+		}
+		if !got.CreatedAt.Equal(alice.CreatedAt) || got.UpdatedAt.Before(alice.UpdatedAt) {
+			t.Fatalf("UpdateOne changed created_at or moved updated_at backwards: %+v", got)
+		}
+		want := update
+		want.Id, want.CreatedAt, want.UpdatedAt = alice.Id, alice.CreatedAt, got.UpdatedAt
+		assertUser(t, *got, want)
+
+		other, err := repo.FindById(t.Context(), bob.Id)
+		if err != nil {
+			t.Fatalf("FindById(%d): %v", bob.Id, err)
+		}
+		assertUser(t, *other, bob)
+	})
+
+	t.Run("UpdateOneMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.UpdateOne(t.Context(), &entities.User{Username: "ghost", Email: "ghost@example.com", Password: "secret"}, 7); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("UpdateOne(7) = %v, want ErrNotFound", err)
+		}
+		if _, err := repo.FindById(t.Context(), 7); err == nil {
+			t.Fatal("UpdateOne on a missing id created a user")
+		}
+	})
+
+	t.Run("DeleteOne", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveUser(t, repo, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		mustSaveUser(t, repo, entities.User{Username: "bob", Email: "bob@example.com", Password: "secret"})
+
+		if err := repo.DeleteOne(t.Context(), 1); err != nil {
+			t.Fatalf("DeleteOne(1): %v", err)
+		}
+		if _, err := repo.FindById(t.Context(), 1); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById(1) of a deleted user returned %v, want ErrNotFound", err)
+		}
+
+		users, _ := findUsers(t, repo, entities.UserQuery{})
+		if len(users) != 1 || users[0].Username != "bob" {
+			t.Fatalf("Find after delete = %+v, want only bob", users)
+		}
+	})
+
+	t.Run("DeleteOneMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSaveUser(t, repo, entities.User{Username: "alice", Email: "alice@example.com", Password: "secret"})
+		if err := repo.DeleteOne(t.Context(), 42); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("DeleteOne(42) = %v, want ErrNotFound", err)
+		}
+		if _, err := repo.FindById(t.Context(), 1); err != nil {
+			t.Fatalf("DeleteOne on a missing id removed another user: %v", err)
+		}
+	})
+}
+
+// mustSaveUser saves user and returns it with the generated id and timestamps.
+func mustSaveUser(t *testing.T, repo port.UserOutbound, user entities.User) entities.User {
+	t.Helper()
+	id, err := repo.Save(t.Context(), &user)
+	if err != nil {
+		t.Fatalf("Save(%+v): %v", user, err)
+	}
+	if id <= 0 || user.Id != id {
+		t.Fatalf("Save returned id %d and set user.Id %d, want the same positive id", id, user.Id)
+	}
+	return user
+}
+
+// findUsers normalizes query and returns the page it selects.
+func findUsers(t *testing.T, repo port.UserOutbound, query entities.UserQuery) ([]entities.User, entities.PageInfo) {
+	t.Helper()
+	if err := query.Normalize(); err != nil {
+		t.Fatalf("Normalize(%+v): %v", query, err)
+	}
+	users, page, err := repo.Find(t.Context(), query)
+	if err != nil {
+		t.Fatalf("Find(%+v): %v", query, err)
+	}
+	return users, page
+}
+
+func assertUsernames(t *testing.T, got []entities.User, want ...string) {
+	t.Helper()
+	names := make([]string, 0, len(got))
+	for _, user := range got {
+		names = append(names, user.Username)
+	}
+	if !slices.Equal(names, want) {
+		t.Fatalf("got usernames %q, want %q", names, want)
+	}
+}
+
+func assertUser(t *testing.T, got, want entities.User) {
+	t.Helper()
+	if got.Id != want.Id || got.Username != want.Username || got.Email != want.Email || got.Password != want.Password ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Fatalf("got user %+v, want %+v", got, want)
+	}
+}
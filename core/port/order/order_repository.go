@@ -1,11 +1,19 @@
 package port
 
-import "github.com/wittawat/go-hex/core/entities"
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
 
 // outbound
 type OrderRepository interface {
-	Save(order *entities.Order) error
-	FindByUserId(userId int) ([]entities.Product, error)
-	UpdateOne(order *entities.Order, id int) error
-	DeleteOne(id int) error
+	// Save stores the order together with its items.
+	Save(ctx context.Context, order *entities.Order) (int, error)
+	FindById(ctx context.Context, id int) (*entities.Order, error)
+	FindByUserId(ctx context.Context, userId int) ([]entities.Order, error)
+	// UpdateStatus moves the order from one status to another. It fails with a
+	// conflict if the order is no longer in status from.
+	UpdateStatus(ctx context.Context, id int, from, to entities.OrderStatus) error
+	DeleteOne(ctx context.Context, id int) error
 }
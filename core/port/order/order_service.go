@@ -1,11 +1,29 @@
 package port
 
-import "github.com/wittawat/go-hex/core/entities"
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
 
 // inbound
 type OrderService interface {
-	Create(order *entities.Order) error
-	GetByUser(userId int) ([]entities.Product, error)
-	Update(order *entities.Order, id int) error
-	Delete(id int) error
+	// Create places a pending order for the caller in ctx, whatever UserId it
+	// carries. Only the product id and quantity of each item are read; titles,
+	// prices and the total are filled in from the catalogue.
+	Create(ctx context.Context, order *entities.Order) (int, error)
+	GetById(ctx context.Context, id int) (*entities.Order, error)
+	GetByUser(ctx context.Context, userId int) ([]entities.Order, error)
+	// UpdateStatus moves the order along its lifecycle; see entities.OrderStatus.
+	UpdateStatus(ctx context.Context, id int, status entities.OrderStatus) (*entities.Order, error)
+	// Delete removes a pending or cancelled order.
+	Delete(ctx context.Context, id int) error
+	// Pay asks the payment gateway for the total of a pending order and marks
+	// the order paid once approved. If the gateway does not answer in time the
+	// payment is returned still pending, to be settled by its callback.
+	Pay(ctx context.Context, id int) (*entities.Payment, error)
+	GetPayments(ctx context.Context, id int) ([]entities.Payment, error)
+	// SettlePayment applies a decision the gateway sent through a callback.
+	// Applying the same decision again changes nothing.
+	SettlePayment(ctx context.Context, result entities.PaymentResult) (*entities.Payment, error)
 }
@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// ErrGatewayTimeout means the gateway did not answer in time. The payment may
+// still go through; its outcome arrives later through a callback.
+var ErrGatewayTimeout = errors.New("payment gateway timed out")
+
+// outbound
+type PaymentGateway interface {
+	// Authorize asks the gateway to take payment.Amount under payment.Reference.
+	// Retrying with the same reference never charges twice.
+	Authorize(ctx context.Context, payment entities.Payment) (entities.PaymentResult, error)
+}
@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// outbound
+type PaymentRepository interface {
+	// Save fails with a conflict if payment is pending and its order already
+	// has a pending payment.
+	Save(ctx context.Context, payment *entities.Payment) (int, error)
+	FindByReference(ctx context.Context, reference string) (*entities.Payment, error)
+	// FindByOrderId returns the attempts to pay for an order, oldest first.
+	FindByOrderId(ctx context.Context, orderId int) ([]entities.Payment, error)
+	// Settle records the gateway's decision on a pending payment. It fails with
+	// a conflict if the payment is no longer pending.
+	Settle(ctx context.Context, id int, result entities.PaymentResult) error
+	// MarkRefundDue sets RefundDue on an authorised payment. It fails with a
+	// conflict if the payment is not authorised.
+	MarkRefundDue(ctx context.Context, id int) error
+}
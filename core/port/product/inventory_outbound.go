@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// InventoryOutbound keeps product stock levels. Reservations must be atomic:
+// concurrent calls to Reserve never take a product's stock below zero.
+type InventoryOutbound interface {
+	// Reserve takes the quantity of every item out of stock, or nothing at all
+	// if any product is short, in which case it fails with a conflict.
+	Reserve(ctx context.Context, items []entities.OrderItem) error
+	// Release puts the quantity of every item back into stock.
+	Release(ctx context.Context, items []entities.OrderItem) error
+	SetStock(ctx context.Context, productId int, stock uint) error
+}
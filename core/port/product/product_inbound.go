@@ -1,11 +1,16 @@
 package port
 
-import "github.com/wittawat/go-hex/core/entities"
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
 
 type ProductInbound interface {
-	Save(product *entities.Product) error
-	FindById(id int) (*entities.Product, error)
-	Find() ([]entities.Product, error)
-	UpdateOne(product *entities.Product, id int) error
-	DeleteOne(id int) error
+	Save(ctx context.Context, product *entities.Product) (int, error)
+	FindById(ctx context.Context, id int) (*entities.Product, error)
+	Find(ctx context.Context, query entities.ProductQuery) ([]entities.Product, entities.PageInfo, error)
+	UpdateOne(ctx context.Context, product *entities.Product, id int) error
+	DeleteOne(ctx context.Context, id int) error
+	SetStock(ctx context.Context, id int, stock uint) error
 }
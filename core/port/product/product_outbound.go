@@ -1,11 +1,19 @@
 package port
 
-import "github.com/wittawat/go-hex/core/entities"
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
 
 type ProductOutbound interface {
-	Save(product *entities.Product) error
-	FindById(id int) (*entities.Product, error)
-	Find() ([]entities.Product, error)
-	UpdateOne(product *entities.Product, id int) error
-	DeleteOne(id int) error
+	Save(ctx context.Context, product *entities.Product) (int, error)
+	FindById(ctx context.Context, id int) (*entities.Product, error)
+	// Find returns one page of products; query must be normalized.
+	Find(ctx context.Context, query entities.ProductQuery) ([]entities.Product, entities.PageInfo, error)
+	// UpdateOne leaves the stock level alone; change it through InventoryOutbound.
+	UpdateOne(ctx context.Context, product *entities.Product, id int) error
+	// DeleteOne hides the product from every other method, even while orders
+	// still refer to it; those orders keep their items.
+	DeleteOne(ctx context.Context, id int) error
 }
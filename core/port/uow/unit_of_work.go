@@ -0,0 +1,12 @@
+package port
+
+import "context"
+
+// UnitOfWork runs several repository calls atomically. Calls made with the
+// context handed to fn take part in the unit; if fn returns an error, none of
+// their writes are kept.
+type UnitOfWork interface {
+	// Do commits the unit if fn returns nil. A Do nested inside another joins
+	// the enclosing unit, which alone decides whether to commit.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,9 @@
+package port // secondary port
+
+import "context"
+
+type PasswordHasher interface {
+	Hash(ctx context.Context, password string) (string, error)
+	// Compare returns nil when password matches hash.
+	Compare(ctx context.Context, hash, password string) error
+}
@@ -1,11 +1,22 @@
 package port // primary port
 
-import "github.com/wittawat/go-hex/core/entities"
+import (
+	"context"
 
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Methods are authorized against the identity carried by ctx.
 type UserInbound interface {
-	Save(user *entities.User) error
-	FindById(id int) (*entities.User, error)
-	Find() ([]entities.User, error)
-	UpdateOne(user *entities.User, id int) error
-	DeleteOne(id int) error
+	// Save registers a customer; the role on user is ignored.
+	Save(ctx context.Context, user *entities.User) (int, error)
+	FindById(ctx context.Context, id int) (*entities.User, error)
+	Find(ctx context.Context, query entities.UserQuery) ([]entities.User, entities.PageInfo, error)
+	UpdateOne(ctx context.Context, user *entities.User, id int) error
+	DeleteOne(ctx context.Context, id int) error
+	// VerifyCredentials returns the user owning email when password matches.
+	VerifyCredentials(ctx context.Context, email, password string) (*entities.User, error)
+	// EnsureAdmin creates the admin account, or promotes the existing user with that
+	// email and resets their password to the given one.
+	EnsureAdmin(ctx context.Context, username, email, password string) error
 }
@@ -1,11 +1,17 @@
 package port // secondary port
 
-import "github.com/wittawat/go-hex/core/entities"
+import (
+	"context"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
 
 type UserOutbound interface {
-	Save(user *entities.User) error
-	FindById(id int) (*entities.User, error)
-	Find() ([]entities.User, error)
-	UpdateOne(user *entities.User, id int) error
-	DeleteOne(id int) error
+	Save(ctx context.Context, user *entities.User) (int, error)
+	FindById(ctx context.Context, id int) (*entities.User, error)
+	FindByEmail(ctx context.Context, email string) (*entities.User, error)
+	// Find returns one page of users; query must be normalized.
+	Find(ctx context.Context, query entities.UserQuery) ([]entities.User, entities.PageInfo, error)
+	UpdateOne(ctx context.Context, user *entities.User, id int) error
+	DeleteOne(ctx context.Context, id int) error
 }
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/auth"
+	userPort "github.com/wittawat/go-hex/core/port/user"
+)
+
+var ErrInvalidToken = entities.Unauthorized("invalid or expired token")
+
+type AuthService struct {
+	users  userPort.UserInbound
+	tokens port.TokenProvider
+}
+
+func NewAuthService(users userPort.UserInbound, tokens port.TokenProvider) port.AuthInbound {
+	return &AuthService{users: users, tokens: tokens}
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (*entities.TokenPair, error) {
+	user, err := s.users.VerifyCredentials(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, entities.Identity{UserId: user.Id, Email: user.Email, Role: user.Role})
+}
+
+// Refresh issues a new pair as long as the refresh token is valid and its user
+// still exists. The new tokens carry the user's current role.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*entities.TokenPair, error) {
+	identity, err := s.tokens.Parse(ctx, refreshToken, entities.RefreshToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	// the user looks itself up, which the policy always allows
+	user, err := s.users.FindById(entities.ContextWithIdentity(ctx, identity), identity.UserId)
+	if errors.Is(err, entities.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, entities.Identity{UserId: user.Id, Email: user.Email, Role: user.Role})
+}
+
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*entities.Identity, error) {
+	identity, err := s.tokens.Parse(ctx, accessToken, entities.AccessToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return identity, nil
+}
+
+func (s *AuthService) issue(ctx context.Context, identity entities.Identity) (*entities.TokenPair, error) {
+	access, accessExpiresAt, err := s.tokens.Issue(ctx, identity, entities.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	refresh, refreshExpiresAt, err := s.tokens.Issue(ctx, identity, entities.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.TokenPair{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExpiresAt,
+		TokenType:        "Bearer",
+	}, nil
+}
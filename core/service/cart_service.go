@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/cart"
+	orderPort "github.com/wittawat/go-hex/core/port/order"
+	productPort "github.com/wittawat/go-hex/core/port/product"
+	uowPort "github.com/wittawat/go-hex/core/port/uow"
+)
+
+type CartService struct {
+	repo     port.CartRepository
+	products productPort.ProductOutbound
+	orders   orderPort.OrderService
+	uow      uowPort.UnitOfWork
+}
+
+func NewCartService(repo port.CartRepository, products productPort.ProductOutbound, orders orderPort.OrderService, uow uowPort.UnitOfWork) port.CartService {
+	return &CartService{repo: repo, products: products, orders: orders, uow: uow}
+}
+
+func (s *CartService) Get(ctx context.Context) (*entities.Cart, error) {
+	userId, err := cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx, userId)
+}
+
+func (s *CartService) AddItem(ctx context.Context, productId int, quantity uint) (*entities.Cart, error) {
+	return s.setItem(ctx, productId, func(current uint, found bool) (uint, error) {
+		// checked before adding, since current+quantity can wrap around
+		if quantity > entities.MaxItemQuantity-min(current, entities.MaxItemQuantity) {
+			var v entities.Violations
+			v.Add("quantity", fmt.Sprintf("would take the cart past %d of product %d", entities.MaxItemQuantity, productId))
+			return 0, v.Err()
+		}
+		return current + quantity, nil
+	})
+}
+
+func (s *CartService) UpdateItem(ctx context.Context, productId int, quantity uint) (*entities.Cart, error) {
+	return s.setItem(ctx, productId, func(current uint, found bool) (uint, error) {
+		if !found {
+			return 0, entities.NotFound("product %d is not in the cart", productId)
+		}
+		return quantity, nil
+	})
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, productId int) (*entities.Cart, error) {
+	userId, err := cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveItem(ctx, userId, productId); err != nil {
+		return nil, err
+	}
+	return s.load(ctx, userId)
+}
+
+func (s *CartService) Clear(ctx context.Context) error {
+	userId, err := cartOwner(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Clear(ctx, userId); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CartService) Checkout(ctx context.Context) (*entities.Order, error) {
+	userId, err := cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var order entities.Order
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		items, err := s.repo.FindByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			_, err := s.products.FindById(ctx, int(item.ProductId))
+			if errors.Is(err, entities.ErrNotFound) {
+				// deleted since it was added, as in load
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			order.Items = append(order.Items, entities.OrderItem{ProductId: item.ProductId, Quantity: item.Quantity})
+		}
+		if len(order.Items) > 0 {
+			// Create joins this unit, so a failed reservation keeps the cart intact.
+			if _, err := s.orders.Create(ctx, &order); err != nil {
+				return err
+			}
+		}
+		// takes the lines of deleted products along too
+		return s.repo.Clear(ctx, userId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(order.Items) == 0 {
+		return nil, entities.Conflict("cart is empty")
+	}
+	return &order, nil
+}
+
+// setItem stores the quantity next returns for the product, given the quantity
+// already in the cart.
+func (s *CartService) setItem(ctx context.Context, productId int, next func(current uint, found bool) (uint, error)) (*entities.Cart, error) {
+	userId, err := cartOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		items, err := s.repo.FindByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		var current uint
+		i := slices.IndexFunc(items, func(item entities.CartItem) bool { return item.ProductId == uint(productId) })
+		if i >= 0 {
+			current = items[i].Quantity
+		}
+		quantity, err := next(current, i >= 0)
+		if err != nil {
+			return err
+		}
+
+		if i < 0 {
+			product, err := s.products.FindById(ctx, productId)
+			if errors.Is(err, entities.ErrNotFound) {
+				var v entities.Violations
+				v.Add("product_id", "does not exist")
+				return v.Err()
+			}
+			if err != nil {
+				return err
+			}
+			if err := s.checkCurrency(ctx, items, product.Price.Currency); err != nil {
+				return err
+			}
+		}
+
+		item := entities.CartItem{ProductId: uint(productId), Quantity: quantity}
+		if err := item.Validate(); err != nil {
+			return err
+		}
+		return s.repo.SetItem(ctx, userId, productId, quantity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx, userId)
+}
+
+// checkCurrency rejects adding a product priced in currency to a cart whose
+// items are priced in another one.
+func (s *CartService) checkCurrency(ctx context.Context, items []entities.CartItem, currency entities.Currency) error {
+	for _, item := range items {
+		product, err := s.products.FindById(ctx, int(item.ProductId))
+		if errors.Is(err, entities.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if product.Price.Currency == currency {
+			return nil
+		}
+		var v entities.Violations
+		v.Add("product_id", fmt.Sprintf("is priced in %s, not %s like the rest of the cart", currency, product.Price.Currency))
+		return v.Err()
+	}
+	return nil
+}
+
+// load returns the user's cart priced from the catalogue.
+func (s *CartService) load(ctx context.Context, userId int) (*entities.Cart, error) {
+	items, err := s.repo.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := entities.Cart{UserId: uint(userId), Items: []entities.CartItem{}}
+	for _, item := range items {
+		product, err := s.products.FindById(ctx, int(item.ProductId))
+		if errors.Is(err, entities.ErrNotFound) {
+			// deleted since it was added
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		item.Title, item.UnitPrice = product.Title, product.Price
+		cart.Items = append(cart.Items, item)
+	}
+	if err := cart.ComputeTotal(); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func cartOwner(ctx context.Context) (int, error) {
+	actor := actorOf(ctx)
+	if actor.UserId == 0 {
+		return 0, entities.Unauthorized("sign in to use a cart")
+	}
+	return actor.UserId, nil
+}
@@ -0,0 +1,138 @@
+package service_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	cartAdapter "github.com/wittawat/go-hex/adapter/cart"
+	"github.com/wittawat/go-hex/core/entities"
+	cartPort "github.com/wittawat/go-hex/core/port/cart"
+	"github.com/wittawat/go-hex/core/service"
+)
+
+// cartFixture is a CartService checking out through the OrderService of an
+// orderFixture.
+type cartFixture struct {
+	*orderFixture
+	cart  cartPort.CartService
+	carts *cartAdapter.MemoryCartRepository
+}
+
+func newCartFixture(t *testing.T) *cartFixture {
+	t.Helper()
+	f := &cartFixture{orderFixture: newOrderFixture(t), carts: cartAdapter.NewMemoryCartRepository()}
+	f.cart = service.NewCartService(f.carts, f.products, f.service, f.uow)
+	return f
+}
+
+func (f *cartFixture) add(t *testing.T, productId int, quantity uint) {
+	t.Helper()
+	if _, err := f.cart.AddItem(as(t, customer), productId, quantity); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f *cartFixture) assertCartEmpty(t *testing.T) {
+	t.Helper()
+	items, err := f.carts.FindByUserId(t.Context(), customer.UserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Errorf("cart holds %+v, want it empty", items)
+	}
+}
+
+func TestCartServiceCheckout(t *testing.T) {
+	f := newCartFixture(t)
+	keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+	mouse := f.seedProduct(t, "mouse", 59000, 5)
+	f.add(t, keyboard, 2)
+	f.add(t, mouse, 1)
+
+	order, err := f.cart.Checkout(as(t, customer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order.Items) != 2 {
+		t.Errorf("order has %d items, want 2", len(order.Items))
+	}
+	f.assertStatus(t, order.Id, entities.OrderPending)
+	f.assertStock(t, keyboard, 3)
+	f.assertStock(t, mouse, 4)
+	f.assertCartEmpty(t)
+
+	if _, err := f.cart.Checkout(as(t, customer)); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("Checkout of an empty cart = %v, want ErrConflict", err)
+	}
+}
+
+func TestCartServiceCheckoutDropsDeletedProducts(t *testing.T) {
+	t.Run("alongside others", func(t *testing.T) {
+		f := newCartFixture(t)
+		keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+		mouse := f.seedProduct(t, "mouse", 59000, 5)
+		f.add(t, keyboard, 2)
+		f.add(t, mouse, 1)
+		if err := f.products.DeleteOne(t.Context(), mouse); err != nil {
+			t.Fatal(err)
+		}
+
+		order, err := f.cart.Checkout(as(t, customer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(order.Items) != 1 || order.Items[0].ProductId != uint(keyboard) {
+			t.Errorf("order items = %+v, want only the keyboard", order.Items)
+		}
+		f.assertStock(t, keyboard, 3)
+		f.assertCartEmpty(t)
+	})
+
+	t.Run("alone", func(t *testing.T) {
+		f := newCartFixture(t)
+		mouse := f.seedProduct(t, "mouse", 59000, 5)
+		f.add(t, mouse, 1)
+		if err := f.products.DeleteOne(t.Context(), mouse); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := f.cart.Checkout(as(t, customer)); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Checkout = %v, want ErrConflict", err)
+		}
+		f.assertCartEmpty(t)
+	})
+}
+
+func TestCartServiceAddItemCapsQuantity(t *testing.T) {
+	tests := []struct {
+		name  string
+		first uint
+		more  uint
+	}{
+		{name: "past the cap", first: entities.MaxItemQuantity, more: 1},
+		{name: "wrapping around", first: 5, more: math.MaxUint - 2},
+		{name: "huge on an empty line", more: math.MaxUint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCartFixture(t)
+			keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+			if tt.first > 0 {
+				f.add(t, keyboard, tt.first)
+			}
+
+			if _, err := f.cart.AddItem(as(t, customer), keyboard, tt.more); !errors.Is(err, entities.ErrValidation) {
+				t.Fatalf("AddItem = %v, want ErrValidation", err)
+			}
+			items, err := f.carts.FindByUserId(t.Context(), customer.UserId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.first == 0 && len(items) != 0 || tt.first > 0 && (len(items) != 1 || items[0].Quantity != tt.first) {
+				t.Errorf("cart = %+v, want it unchanged", items)
+			}
+		})
+	}
+}
@@ -1,43 +1,394 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
 	"github.com/wittawat/go-hex/core/entities"
 	port "github.com/wittawat/go-hex/core/port/order"
+	paymentPort "github.com/wittawat/go-hex/core/port/payment"
+	productPort "github.com/wittawat/go-hex/core/port/product"
+	uowPort "github.com/wittawat/go-hex/core/port/uow"
 )
 
 type OrderService struct {
-	repo port.OrderRepository
+	repo      port.OrderRepository
+	products  productPort.ProductOutbound
+	inventory productPort.InventoryOutbound
+	payments  paymentPort.PaymentRepository
+	gateway   paymentPort.PaymentGateway
+	// retryAfter is how long a payment may stay pending before Pay asks the
+	// gateway about it again.
+	retryAfter time.Duration
+	uow        uowPort.UnitOfWork
+	policy     Policy
+	logger     *slog.Logger
 }
 
-func NewOrderService(repo port.OrderRepository) port.OrderService {
-	return &OrderService{repo: repo}
+func NewOrderService(repo port.OrderRepository, products productPort.ProductOutbound, inventory productPort.InventoryOutbound,
+	payments paymentPort.PaymentRepository, gateway paymentPort.PaymentGateway, retryAfter time.Duration, uow uowPort.UnitOfWork, logger *slog.Logger) port.OrderService {
+	return &OrderService{repo: repo, products: products, inventory: inventory, payments: payments, gateway: gateway, retryAfter: retryAfter, uow: uow, logger: logger}
 }
 
-func (s *OrderService) Create(order *entities.Order) error {
-	if err := s.repo.Save(order); err != nil {
+func (s *OrderService) Create(ctx context.Context, order *entities.Order) (int, error) {
+	actor := actorOf(ctx)
+	if actor.UserId == 0 {
+		return 0, entities.Unauthorized("sign in to place an order")
+	}
+	order.UserId = uint(actor.UserId)
+	order.Status = entities.OrderPending
+	if err := order.Validate(); err != nil {
+		return 0, err
+	}
+	if err := s.priceItems(ctx, order); err != nil {
+		return 0, err
+	}
+	if err := order.ComputeTotal(); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.inventory.Reserve(ctx, order.Items); err != nil {
+			return err
+		}
+		var err error
+		id, err = s.repo.Save(ctx, order)
 		return err
+	})
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	s.logger.InfoContext(ctx, "order placed", "order_id", id, "user_id", order.UserId, "total", order.Total.String())
+	return id, nil
 }
 
-func (s *OrderService) GetByUser(userId int) ([]entities.Product, error) {
-	products, err := s.repo.FindByUserId(userId)
+func (s *OrderService) GetById(ctx context.Context, id int) (*entities.Order, error) {
+	order, err := s.repo.FindById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return products, nil
+	if err := s.policy.AccessOrders(actorOf(ctx), int(order.UserId)); err != nil {
+		return nil, err
+	}
+	return order, nil
 }
 
-func (s *OrderService) Update(order *entities.Order, id int) error {
-	if err := s.repo.UpdateOne(order, id); err != nil {
+func (s *OrderService) GetByUser(ctx context.Context, userId int) ([]entities.Order, error) {
+	if err := s.policy.AccessOrders(actorOf(ctx), userId); err != nil {
+		return nil, err
+	}
+	orders, err := s.repo.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *OrderService) UpdateStatus(ctx context.Context, id int, status entities.OrderStatus) (*entities.Order, error) {
+	order, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.ChangeOrderStatus(actorOf(ctx), int(order.UserId), order.Status, status); err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(status) {
+		return nil, entities.Conflict("cannot move a %s order to %s", order.Status, status)
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateStatus(ctx, id, order.Status, status); err != nil {
+			return err
+		}
+		if status != entities.OrderCancelled {
+			return nil
+		}
+		if err := s.inventory.Release(ctx, order.Items); err != nil {
+			return err
+		}
+		if order.Status == entities.OrderPending {
+			return nil
+		}
+		return s.refundPayments(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindById(ctx, id)
+}
+
+func (s *OrderService) Delete(ctx context.Context, id int) error {
+	order, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.policy.AccessOrders(actorOf(ctx), int(order.UserId)); err != nil {
+		return err
+	}
+	if order.Status != entities.OrderPending && order.Status != entities.OrderCancelled {
+		return entities.Conflict("cannot delete a %s order", order.Status)
+	}
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Payments are kept for good, a refund due on one above all, and the
+		// database refuses to orphan them.
+		payments, err := s.payments.FindByOrderId(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(payments) > 0 {
+			return entities.Conflict("order %d has payments and cannot be deleted", id)
+		}
+		// Cancel a pending order first so its stock is released exactly once.
+		if order.Status == entities.OrderPending {
+			if err := s.repo.UpdateStatus(ctx, id, entities.OrderPending, entities.OrderCancelled); err != nil {
+				return err
+			}
+			if err := s.inventory.Release(ctx, order.Items); err != nil {
+				return err
+			}
+		}
+		return s.repo.DeleteOne(ctx, id)
+	})
+	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *OrderService) Delete(id int) error {
-	if err := s.repo.DeleteOne(id); err != nil {
+// Pay asks the gateway to charge for a pending order. The attempt is claimed in
+// its own unit first, so that concurrent calls cannot both charge the order.
+func (s *OrderService) Pay(ctx context.Context, id int) (*entities.Payment, error) {
+	if _, err := s.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	payment, err := s.claimPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.gateway.Authorize(ctx, *payment)
+	if errors.Is(err, paymentPort.ErrGatewayTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		s.logger.WarnContext(ctx, "payment gateway did not answer, payment left pending", "order_id", id, "reference", payment.Reference)
+		return payment, nil
+	}
+	if err != nil {
+		// The gateway was not reached or gave up, so nothing was charged. The
+		// attempt is closed, even if ctx was cancelled, to let the order be paid again.
+		s.logger.WarnContext(ctx, "payment gateway failed, payment declined", "order_id", id, "reference", payment.Reference, "error", err)
+		declined := entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentDeclined, Reason: "gateway error: " + err.Error()}
+		if _, settleErr := s.settle(context.WithoutCancel(ctx), payment, declined); settleErr != nil {
+			return nil, errors.Join(err, settleErr)
+		}
+		return nil, err
+	}
+	settled, err := s.settle(ctx, payment, result)
+	if err != nil {
+		return nil, err
+	}
+	if settled.Status == entities.PaymentDeclined {
+		return nil, entities.Declined("payment declined: %s", settled.Reason)
+	}
+	return settled, nil
+}
+
+// claimPayment records a new pending payment for order id. A pending payment
+// older than s.retryAfter is returned instead, to be authorised again under
+// its reference, which the gateway never charges twice.
+func (s *OrderService) claimPayment(ctx context.Context, id int) (*entities.Payment, error) {
+	var payment *entities.Payment
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		order, err := s.repo.FindById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != entities.OrderPending {
+			return entities.Conflict("cannot pay for a %s order", order.Status)
+		}
+		payments, err := s.payments.FindByOrderId(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, pending := range payments {
+			if pending.Status != entities.PaymentPending {
+				continue
+			}
+			if time.Since(pending.UpdatedAt) < s.retryAfter {
+				return entities.Conflict("order %d already has payment %s in progress", id, pending.Reference)
+			}
+			s.logger.InfoContext(ctx, "asking the payment gateway again about a stale payment", "order_id", id, "reference", pending.Reference)
+			payment = &pending
+			return nil
+		}
+
+		reference, err := newPaymentReference()
+		if err != nil {
+			return err
+		}
+		payment = &entities.Payment{OrderId: uint(order.Id), Reference: reference, Amount: order.Total, Status: entities.PaymentPending}
+		// The repository refuses a second pending payment per order, which
+		// settles a race the check above cannot see.
+		_, err = s.payments.Save(ctx, payment)
+		if errors.Is(err, entities.ErrConflict) {
+			return entities.Conflict("order %d already has a payment in progress", id)
+		}
 		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *OrderService) GetPayments(ctx context.Context, id int) ([]entities.Payment, error) {
+	if _, err := s.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	payments, err := s.payments.FindByOrderId(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (s *OrderService) SettlePayment(ctx context.Context, result entities.PaymentResult) (*entities.Payment, error) {
+	if !result.Status.Settled() {
+		return nil, entities.Validation("payment status must be %s or %s", entities.PaymentAuthorized, entities.PaymentDeclined)
+	}
+	payment, err := s.payments.FindByReference(ctx, result.Reference)
+	if err != nil {
+		return nil, err
+	}
+	return s.settle(ctx, payment, result)
+}
+
+// settle records the gateway's decision on payment and, if approved, marks its
+// order paid. The decision is recorded on its own, since it stands whatever
+// became of the order meanwhile; an approved payment for an order that can no
+// longer be paid is flagged for a refund. A decision already recorded is
+// accepted again and finishes what an earlier delivery left undone.
+func (s *OrderService) settle(ctx context.Context, payment *entities.Payment, result entities.PaymentResult) (*entities.Payment, error) {
+	if payment.Status.Settled() && payment.Status != result.Status {
+		return nil, s.contradicts(ctx, payment, result)
+	}
+	if !payment.Status.Settled() {
+		recorded, err := s.record(ctx, payment, result)
+		if err != nil {
+			return nil, err
+		}
+		payment = recorded
+	}
+
+	if result.Status == entities.PaymentAuthorized && !payment.RefundDue {
+		if err := s.markPaid(ctx, payment); err != nil {
+			return nil, err
+		}
+	}
+	return s.payments.FindByReference(ctx, payment.Reference)
+}
+
+// record stores result on the pending payment, accepting the same decision
+// stored meanwhile by another delivery.
+func (s *OrderService) record(ctx context.Context, payment *entities.Payment, result entities.PaymentResult) (*entities.Payment, error) {
+	err := s.payments.Settle(ctx, payment.Id, result)
+	if err == nil {
+		s.logger.InfoContext(ctx, "payment settled", "order_id", payment.OrderId, "reference", payment.Reference, "status", result.Status, "reason", result.Reason)
+		return payment, nil
+	}
+	if !errors.Is(err, entities.ErrConflict) {
+		return nil, err
+	}
+	current, err := s.payments.FindByReference(ctx, payment.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != result.Status {
+		return nil, s.contradicts(ctx, current, result)
+	}
+	return current, nil
+}
+
+// contradicts reports a decision on payment other than the one recorded.
+func (s *OrderService) contradicts(ctx context.Context, payment *entities.Payment, result entities.PaymentResult) error {
+	s.logger.WarnContext(ctx, "payment decision contradicts the recorded one",
+		"reference", payment.Reference, "recorded", payment.Status, "received", result.Status)
+	return entities.Conflict("payment %s is already %s", payment.Reference, payment.Status)
+}
+
+// markPaid moves the order of the authorised payment from pending to paid. An
+// order found cancelled instead leaves the payment flagged for a refund.
+func (s *OrderService) markPaid(ctx context.Context, payment *entities.Payment) error {
+	err := s.repo.UpdateStatus(ctx, int(payment.OrderId), entities.OrderPending, entities.OrderPaid)
+	if !errors.Is(err, entities.ErrConflict) {
+		return err
+	}
+	order, err := s.repo.FindById(ctx, int(payment.OrderId))
+	if err != nil {
+		return err
+	}
+	if order.Status != entities.OrderCancelled {
+		// paid by this payment already, as no other can be authorised
+		return nil
+	}
+	s.logger.WarnContext(ctx, "payment authorised for a cancelled order, refund due", "order_id", payment.OrderId, "reference", payment.Reference)
+	return s.payments.MarkRefundDue(ctx, payment.Id)
+}
+
+// refundPayments flags the authorised payments of order id for a refund.
+func (s *OrderService) refundPayments(ctx context.Context, id int) error {
+	payments, err := s.payments.FindByOrderId(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, payment := range payments {
+		if payment.Status == entities.PaymentAuthorized && !payment.RefundDue {
+			s.logger.InfoContext(ctx, "paid order cancelled, refund due", "order_id", id, "reference", payment.Reference)
+			if err := s.payments.MarkRefundDue(ctx, payment.Id); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
+
+// newPaymentReference returns a reference no other payment uses.
+func newPaymentReference() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "pay_" + hex.EncodeToString(b), nil
+}
+
+// priceItems copies the current title and price of each product onto its item.
+// All items must be priced in the currency of the first one.
+func (s *OrderService) priceItems(ctx context.Context, order *entities.Order) error {
+	var v entities.Violations
+	var currency entities.Currency
+	for i := range order.Items {
+		item := &order.Items[i]
+		product, err := s.products.FindById(ctx, int(item.ProductId))
+		if errors.Is(err, entities.ErrNotFound) {
+			v.Add("items["+strconv.Itoa(i)+"].product_id", "does not exist")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if currency == "" {
+			currency = product.Price.Currency
+		}
+		if product.Price.Currency != currency {
+			v.Add("items["+strconv.Itoa(i)+"].product_id", fmt.Sprintf("is priced in %s, not %s like the rest of the order", product.Price.Currency, currency))
+			continue
+		}
+		item.Title, item.UnitPrice = product.Title, product.Price
+	}
+	return v.Err()
+}
@@ -0,0 +1,513 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wittawat/go-hex/adapter/memtx"
+	orderAdapter "github.com/wittawat/go-hex/adapter/order"
+	paymentAdapter "github.com/wittawat/go-hex/adapter/payment"
+	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	"github.com/wittawat/go-hex/core/entities"
+	orderPort "github.com/wittawat/go-hex/core/port/order"
+	paymentPort "github.com/wittawat/go-hex/core/port/payment"
+	"github.com/wittawat/go-hex/core/service"
+)
+
+var (
+	customer = entities.Identity{UserId: 1, Email: "alice@example.com", Role: entities.RoleCustomer}
+	staff    = entities.Identity{UserId: 2, Email: "bob@example.com", Role: entities.RoleStaff}
+)
+
+// orderFixture is an OrderService over the memory repositories, with the
+// repositories at hand for seeding and inspection.
+type orderFixture struct {
+	service  orderPort.OrderService
+	products *productAdapter.MemoryProductRepository
+	orders   *orderAdapter.MemoryOrderRepository
+	payments *paymentAdapter.MemoryPaymentRepository
+	gateway  *paymentAdapter.FakeGateway
+	uow      *memtx.UnitOfWork
+}
+
+func newOrderFixture(t *testing.T) *orderFixture {
+	t.Helper()
+	f := &orderFixture{
+		products: productAdapter.NewMemoryProductRepository(),
+		orders:   orderAdapter.NewMemoryOrderRepository(),
+		payments: paymentAdapter.NewMemoryPaymentRepository(),
+		gateway:  paymentAdapter.NewFakeGateway(paymentAdapter.FakeApprove, 0),
+		uow:      memtx.NewUnitOfWork(),
+	}
+	f.service = service.NewOrderService(f.orders, f.products, f.products, f.payments, f.gateway, time.Hour, f.uow, slog.New(slog.DiscardHandler))
+	return f
+}
+
+// as returns a context carrying identity as the caller.
+func as(t *testing.T, identity entities.Identity) context.Context {
+	return entities.ContextWithIdentity(t.Context(), &identity)
+}
+
+// seedProduct stores a THB product with stock units and returns its id.
+func (f *orderFixture) seedProduct(t *testing.T, title string, price int64, stock uint) int {
+	t.Helper()
+	product := entities.Product{Title: title, Price: entities.Money{Amount: price, Currency: entities.THB}}
+	id, err := f.products.Save(t.Context(), &product)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.products.SetStock(t.Context(), id, stock); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// placeOrder has customer order quantity units of product productId.
+func (f *orderFixture) placeOrder(t *testing.T, productId int, quantity uint) int {
+	t.Helper()
+	order := entities.Order{Items: []entities.OrderItem{{ProductId: uint(productId), Quantity: quantity}}}
+	id, err := f.service.Create(as(t, customer), &order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func (f *orderFixture) assertStock(t *testing.T, productId int, want uint) {
+	t.Helper()
+	product, err := f.products.FindById(t.Context(), productId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.Stock != want {
+		t.Errorf("product %d stock = %d, want %d", productId, product.Stock, want)
+	}
+}
+
+// serviceWith returns an OrderService over the repositories of f that pays
+// through gateway and asks it again about payments pending for retryAfter.
+func (f *orderFixture) serviceWith(gateway paymentPort.PaymentGateway, retryAfter time.Duration) orderPort.OrderService {
+	return service.NewOrderService(f.orders, f.products, f.products, f.payments, gateway, retryAfter, f.uow, slog.New(slog.DiscardHandler))
+}
+
+// assertPayments checks the status and refund flag of each payment for order
+// orderId, oldest first.
+func (f *orderFixture) assertPayments(t *testing.T, orderId int, want ...entities.Payment) {
+	t.Helper()
+	payments, err := f.payments.FindByOrderId(t.Context(), orderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != len(want) {
+		t.Fatalf("order %d has payments %+v, want %d", orderId, payments, len(want))
+	}
+	for i, payment := range payments {
+		if payment.Status != want[i].Status || payment.RefundDue != want[i].RefundDue {
+			t.Errorf("payment %d of order %d is %s with refund due %t, want %s with %t",
+				i, orderId, payment.Status, payment.RefundDue, want[i].Status, want[i].RefundDue)
+		}
+	}
+}
+
+func (f *orderFixture) assertStatus(t *testing.T, orderId int, want entities.OrderStatus) {
+	t.Helper()
+	order, err := f.orders.FindById(t.Context(), orderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != want {
+		t.Errorf("order %d status = %s, want %s", orderId, order.Status, want)
+	}
+}
+
+func TestOrderServiceCreateReservesStock(t *testing.T) {
+	f := newOrderFixture(t)
+	keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+
+	id := f.placeOrder(t, keyboard, 2)
+	f.assertStock(t, keyboard, 3)
+	f.assertStatus(t, id, entities.OrderPending)
+
+	order := entities.Order{Items: []entities.OrderItem{{ProductId: uint(keyboard), Quantity: 4}}}
+	if _, err := f.service.Create(as(t, customer), &order); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("Create beyond stock = %v, want ErrConflict", err)
+	}
+	f.assertStock(t, keyboard, 3)
+}
+
+func TestOrderServiceCancelReleasesStock(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor entities.Identity
+		paid  bool
+	}{
+		{name: "owner cancels a pending order", actor: customer},
+		{name: "staff cancels a pending order", actor: staff},
+		{name: "staff cancels a paid order", actor: staff, paid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOrderFixture(t)
+			keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+			id := f.placeOrder(t, keyboard, 2)
+			if tt.paid {
+				if _, err := f.service.Pay(as(t, customer), id); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			order, err := f.service.UpdateStatus(as(t, tt.actor), id, entities.OrderCancelled)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if order.Status != entities.OrderCancelled {
+				t.Errorf("UpdateStatus returned a %s order, want cancelled", order.Status)
+			}
+			f.assertStock(t, keyboard, 5)
+			if tt.paid {
+				f.assertPayments(t, id, entities.Payment{Status: entities.PaymentAuthorized, RefundDue: true})
+			}
+
+			// a second cancel must not release the stock again
+			if _, err := f.service.UpdateStatus(as(t, tt.actor), id, entities.OrderCancelled); !errors.Is(err, entities.ErrConflict) {
+				t.Fatalf("second cancel = %v, want ErrConflict", err)
+			}
+			f.assertStock(t, keyboard, 5)
+		})
+	}
+}
+
+func TestOrderServiceUpdateStatusFollowsLifecycle(t *testing.T) {
+	f := newOrderFixture(t)
+	keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+	id := f.placeOrder(t, keyboard, 1)
+
+	if _, err := f.service.UpdateStatus(as(t, staff), id, entities.OrderShipped); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("shipping a pending order = %v, want ErrConflict", err)
+	}
+	if _, err := f.service.Pay(as(t, customer), id); err != nil {
+		t.Fatal(err)
+	}
+	for _, next := range []entities.OrderStatus{entities.OrderShipped, entities.OrderDelivered} {
+		if _, err := f.service.UpdateStatus(as(t, staff), id, next); err != nil {
+			t.Fatalf("move to %s: %v", next, err)
+		}
+	}
+	if _, err := f.service.UpdateStatus(as(t, staff), id, entities.OrderCancelled); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("cancelling a delivered order = %v, want ErrConflict", err)
+	}
+	f.assertStatus(t, id, entities.OrderDelivered)
+	f.assertStock(t, keyboard, 4)
+}
+
+func TestOrderServiceDeleteReleasesStock(t *testing.T) {
+	t.Run("pending order", func(t *testing.T) {
+		f := newOrderFixture(t)
+		keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+		id := f.placeOrder(t, keyboard, 2)
+
+		if err := f.service.Delete(as(t, customer), id); err != nil {
+			t.Fatal(err)
+		}
+		f.assertStock(t, keyboard, 5)
+		if _, err := f.orders.FindById(t.Context(), id); !errors.Is(err, entities.ErrNotFound) {
+			t.Fatalf("FindById after Delete = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("cancelled order releases nothing more", func(t *testing.T) {
+		f := newOrderFixture(t)
+		keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+		id := f.placeOrder(t, keyboard, 2)
+		if _, err := f.service.UpdateStatus(as(t, customer), id, entities.OrderCancelled); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.service.Delete(as(t, customer), id); err != nil {
+			t.Fatal(err)
+		}
+		f.assertStock(t, keyboard, 5)
+	})
+
+	t.Run("paid order is kept", func(t *testing.T) {
+		f := newOrderFixture(t)
+		keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+		id := f.placeOrder(t, keyboard, 2)
+		if _, err := f.service.Pay(as(t, customer), id); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.service.Delete(as(t, customer), id); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Delete of a paid order = %v, want ErrConflict", err)
+		}
+		f.assertStock(t, keyboard, 3)
+		f.assertStatus(t, id, entities.OrderPaid)
+	})
+
+	t.Run("paid then cancelled order keeps its refund due", func(t *testing.T) {
+		f := newOrderFixture(t)
+		keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+		id := f.placeOrder(t, keyboard, 2)
+		if _, err := f.service.Pay(as(t, customer), id); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.service.UpdateStatus(as(t, staff), id, entities.OrderCancelled); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.service.Delete(as(t, customer), id); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Delete of a cancelled order with a refund due = %v, want ErrConflict", err)
+		}
+		f.assertStatus(t, id, entities.OrderCancelled)
+		f.assertPayments(t, id, entities.Payment{Status: entities.PaymentAuthorized, RefundDue: true})
+	})
+
+	t.Run("declined payment keeps a pending order", func(t *testing.T) {
+		f := newOrderFixture(t)
+		keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+		id := f.placeOrder(t, keyboard, 2)
+		f.gateway.SetOutcome(paymentAdapter.FakeDecline)
+		if _, err := f.service.Pay(as(t, customer), id); !errors.Is(err, entities.ErrPaymentDeclined) {
+			t.Fatalf("Pay = %v, want ErrPaymentDeclined", err)
+		}
+
+		if err := f.service.Delete(as(t, customer), id); !errors.Is(err, entities.ErrConflict) {
+			t.Fatalf("Delete of an order with a payment = %v, want ErrConflict", err)
+		}
+		f.assertStatus(t, id, entities.OrderPending)
+		f.assertStock(t, keyboard, 3)
+		f.assertPayments(t, id, declined)
+	})
+}
+
+// gatewayFunc is a PaymentGateway answering with a function.
+type gatewayFunc func(ctx context.Context, payment entities.Payment) (entities.PaymentResult, error)
+
+func (f gatewayFunc) Authorize(ctx context.Context, payment entities.Payment) (entities.PaymentResult, error) {
+	return f(ctx, payment)
+}
+
+var (
+	authorized = entities.Payment{Status: entities.PaymentAuthorized}
+	declined   = entities.Payment{Status: entities.PaymentDeclined}
+	pending    = entities.Payment{Status: entities.PaymentPending}
+)
+
+func TestOrderServicePayApproved(t *testing.T) {
+	f := newOrderFixture(t)
+	id := f.placeOrder(t, f.seedProduct(t, "keyboard", 129000, 5), 1)
+
+	payment, err := f.service.Pay(as(t, customer), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != entities.PaymentAuthorized || payment.Amount.Amount != 129000 {
+		t.Errorf("Pay returned %+v, want an authorized payment of 129000", payment)
+	}
+	f.assertStatus(t, id, entities.OrderPaid)
+	f.assertPayments(t, id, authorized)
+
+	if _, err := f.service.Pay(as(t, customer), id); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("paying a paid order = %v, want ErrConflict", err)
+	}
+}
+
+func TestOrderServicePayDeclined(t *testing.T) {
+	f := newOrderFixture(t)
+	id := f.placeOrder(t, f.seedProduct(t, "keyboard", 129000, 5), 1)
+
+	f.gateway.SetOutcome(paymentAdapter.FakeDecline)
+	if _, err := f.service.Pay(as(t, customer), id); !errors.Is(err, entities.ErrPaymentDeclined) {
+		t.Fatalf("Pay = %v, want ErrPaymentDeclined", err)
+	}
+	f.assertStatus(t, id, entities.OrderPending)
+	f.assertPayments(t, id, declined)
+
+	f.gateway.SetOutcome(paymentAdapter.FakeApprove)
+	if _, err := f.service.Pay(as(t, customer), id); err != nil {
+		t.Fatalf("paying again after a decline: %v", err)
+	}
+	f.assertStatus(t, id, entities.OrderPaid)
+	f.assertPayments(t, id, declined, authorized)
+}
+
+func TestOrderServicePayTimeoutThenCallbacks(t *testing.T) {
+	f := newOrderFixture(t)
+	id := f.placeOrder(t, f.seedProduct(t, "keyboard", 129000, 5), 1)
+
+	f.gateway.SetOutcome(paymentAdapter.FakeTimeout)
+	payment, err := f.service.Pay(as(t, customer), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != entities.PaymentPending {
+		t.Fatalf("Pay returned a %s payment, want pending", payment.Status)
+	}
+	if _, err := f.service.Pay(as(t, customer), id); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("paying while a payment is pending = %v, want ErrConflict", err)
+	}
+
+	approve := entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentAuthorized}
+	for range 2 {
+		// gateways deliver callbacks at least once
+		got, err := f.service.SettlePayment(t.Context(), approve)
+		if err != nil {
+			t.Fatalf("SettlePayment: %v", err)
+		}
+		if got.Status != entities.PaymentAuthorized {
+			t.Fatalf("SettlePayment returned a %s payment, want authorized", got.Status)
+		}
+	}
+	f.assertStatus(t, id, entities.OrderPaid)
+
+	decline := entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentDeclined, Reason: "card declined"}
+	if _, err := f.service.SettlePayment(t.Context(), decline); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("contradictory callback = %v, want ErrConflict", err)
+	}
+	f.assertStatus(t, id, entities.OrderPaid)
+	f.assertPayments(t, id, authorized)
+
+	if _, err := f.service.SettlePayment(t.Context(), entities.PaymentResult{Reference: "pay_x", Status: entities.PaymentAuthorized}); !errors.Is(err, entities.ErrNotFound) {
+		t.Fatalf("callback for an unknown payment = %v, want ErrNotFound", err)
+	}
+	if _, err := f.service.SettlePayment(t.Context(), entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentPending}); !errors.Is(err, entities.ErrValidation) {
+		t.Fatalf("callback without a decision = %v, want ErrValidation", err)
+	}
+}
+
+func TestOrderServicePayAsksAgainAboutStalePayment(t *testing.T) {
+	f := newOrderFixture(t)
+	id := f.placeOrder(t, f.seedProduct(t, "keyboard", 129000, 5), 1)
+	var references []string
+	gateway := gatewayFunc(func(ctx context.Context, payment entities.Payment) (entities.PaymentResult, error) {
+		references = append(references, payment.Reference)
+		return f.gateway.Authorize(ctx, payment)
+	})
+	// every pending payment is stale at once
+	orders := f.serviceWith(gateway, 0)
+
+	f.gateway.SetOutcome(paymentAdapter.FakeTimeout)
+	if _, err := orders.Pay(as(t, customer), id); err != nil {
+		t.Fatal(err)
+	}
+	f.gateway.SetOutcome(paymentAdapter.FakeApprove)
+	payment, err := orders.Pay(as(t, customer), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Status != entities.PaymentAuthorized {
+		t.Errorf("Pay returned a %s payment, want authorized", payment.Status)
+	}
+	if len(references) != 2 || references[0] != references[1] || references[1] != payment.Reference {
+		t.Errorf("gateway asked about %v, want the same reference twice", references)
+	}
+	f.assertStatus(t, id, entities.OrderPaid)
+	f.assertPayments(t, id, authorized)
+}
+
+func TestOrderServicePayGatewayFailureClosesPayment(t *testing.T) {
+	f := newOrderFixture(t)
+	id := f.placeOrder(t, f.seedProduct(t, "keyboard", 129000, 5), 1)
+
+	ctx, cancel := context.WithCancel(as(t, customer))
+	defer cancel()
+	// the caller goes away while the gateway is asked
+	failing := f.serviceWith(gatewayFunc(func(ctx context.Context, payment entities.Payment) (entities.PaymentResult, error) {
+		cancel()
+		return entities.PaymentResult{}, ctx.Err()
+	}), time.Hour)
+	if _, err := failing.Pay(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Pay = %v, want context.Canceled", err)
+	}
+	f.assertPayments(t, id, declined)
+	f.assertStatus(t, id, entities.OrderPending)
+
+	if _, err := f.service.Pay(as(t, customer), id); err != nil {
+		t.Fatalf("paying again after a gateway failure: %v", err)
+	}
+	f.assertPayments(t, id, declined, authorized)
+}
+
+func TestOrderServicePayConcurrently(t *testing.T) {
+	f := newOrderFixture(t)
+	id := f.placeOrder(t, f.seedProduct(t, "keyboard", 129000, 5), 1)
+	orders := f.serviceWith(paymentAdapter.NewFakeGateway(paymentAdapter.FakeApprove, 10*time.Millisecond), time.Hour)
+
+	const callers = 8
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = orders.Pay(as(t, customer), id)
+		}()
+	}
+	wg.Wait()
+
+	var paid int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			paid++
+		case !errors.Is(err, entities.ErrConflict):
+			t.Errorf("Pay = %v, want success or ErrConflict", err)
+		}
+	}
+	if paid != 1 {
+		t.Errorf("%d concurrent Pay calls succeeded, want 1", paid)
+	}
+	f.assertPayments(t, id, authorized)
+}
+
+func TestOrderServicePaymentForCancelledOrderIsDueRefund(t *testing.T) {
+	t.Run("cancelled while the gateway decides", func(t *testing.T) {
+		f := newOrderFixture(t)
+		keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+		id := f.placeOrder(t, keyboard, 2)
+		orders := f.serviceWith(gatewayFunc(func(ctx context.Context, payment entities.Payment) (entities.PaymentResult, error) {
+			if _, err := f.service.UpdateStatus(as(t, customer), id, entities.OrderCancelled); err != nil {
+				t.Error(err)
+			}
+			return entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentAuthorized}, nil
+		}), time.Hour)
+
+		payment, err := orders.Pay(as(t, customer), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if payment.Status != entities.PaymentAuthorized || !payment.RefundDue {
+			t.Errorf("Pay returned %+v, want an authorized payment with a refund due", payment)
+		}
+		f.assertStatus(t, id, entities.OrderCancelled)
+		f.assertStock(t, keyboard, 5)
+		f.assertPayments(t, id, entities.Payment{Status: entities.PaymentAuthorized, RefundDue: true})
+	})
+
+	t.Run("cancelled before the callback", func(t *testing.T) {
+		f := newOrderFixture(t)
+		id := f.placeOrder(t, f.seedProduct(t, "keyboard", 129000, 5), 1)
+		f.gateway.SetOutcome(paymentAdapter.FakeTimeout)
+		payment, err := f.service.Pay(as(t, customer), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.assertPayments(t, id, pending)
+		if _, err := f.service.UpdateStatus(as(t, customer), id, entities.OrderCancelled); err != nil {
+			t.Fatal(err)
+		}
+
+		approve := entities.PaymentResult{Reference: payment.Reference, Status: entities.PaymentAuthorized}
+		for range 2 {
+			if _, err := f.service.SettlePayment(t.Context(), approve); err != nil {
+				t.Fatalf("SettlePayment: %v", err)
+			}
+		}
+		f.assertStatus(t, id, entities.OrderCancelled)
+		f.assertPayments(t, id, entities.Payment{Status: entities.PaymentAuthorized, RefundDue: true})
+	})
+}
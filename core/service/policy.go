@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+// Policy holds the authorization rules. The services consult it before touching
+// a repository, so every inbound adapter is held to the same rules.
+type Policy struct{}
+
+// ManageProducts covers creating, updating and deleting products.
+func (Policy) ManageProducts(actor entities.Identity) error {
+	return allowRoles(actor, entities.RoleStaff, entities.RoleAdmin)
+}
+
+func (Policy) ListUsers(actor entities.Identity) error {
+	return allowRoles(actor, entities.RoleAdmin)
+}
+
+func (Policy) DeleteUser(actor entities.Identity) error {
+	return allowRoles(actor, entities.RoleAdmin)
+}
+
+// AccessUser covers reading and updating a single user record.
+func (Policy) AccessUser(actor entities.Identity, userId int) error {
+	if actor.UserId != 0 && actor.UserId == userId {
+		return nil
+	}
+	return allowRoles(actor, entities.RoleAdmin)
+}
+
+func (Policy) ChangeRole(actor entities.Identity) error {
+	return allowRoles(actor, entities.RoleAdmin)
+}
+
+// AccessOrders covers reading and modifying the orders owned by ownerId.
+func (Policy) AccessOrders(actor entities.Identity, ownerId int) error {
+	if actor.UserId != 0 && actor.UserId == ownerId {
+		return nil
+	}
+	return allowRoles(actor, entities.RoleStaff, entities.RoleAdmin)
+}
+
+// ChangeOrderStatus lets staff run the whole lifecycle; an owner may only
+// cancel an order that is still pending. Once paid, cancelling it means
+// refunding the payment, which is left to staff.
+func (p Policy) ChangeOrderStatus(actor entities.Identity, ownerId int, current, next entities.OrderStatus) error {
+	if next != entities.OrderCancelled {
+		return allowRoles(actor, entities.RoleStaff, entities.RoleAdmin)
+	}
+	if err := p.AccessOrders(actor, ownerId); err != nil {
+		return err
+	}
+	// moves the lifecycle forbids anyway are left for the caller to reject as a conflict
+	if current != entities.OrderPending && current.CanTransitionTo(next) && !actor.HasRole(entities.RoleStaff, entities.RoleAdmin) {
+		return entities.Forbidden("only staff may cancel a %s order", current)
+	}
+	return nil
+}
+
+func allowRoles(actor entities.Identity, roles ...entities.Role) error {
+	if actor.HasRole(roles...) {
+		return nil
+	}
+	return entities.Forbidden("requires role %s", joinRoles(roles))
+}
+
+func joinRoles(roles []entities.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return strings.Join(names, " or ")
+}
+
+// actorOf returns the caller stored in ctx by the inbound adapter. An anonymous
+// caller is the zero identity, which only passes checks that need no role.
+func actorOf(ctx context.Context) entities.Identity {
+	if identity, ok := entities.IdentityFromContext(ctx); ok {
+		return *identity
+	}
+	return entities.Identity{}
+}
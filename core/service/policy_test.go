@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wittawat/go-hex/core/entities"
+)
+
+func TestPolicyChangeOrderStatus(t *testing.T) {
+	const ownerId = 7
+	owner := entities.Identity{UserId: ownerId, Role: entities.RoleCustomer}
+	stranger := entities.Identity{UserId: 8, Role: entities.RoleCustomer}
+	staff := entities.Identity{UserId: 9, Role: entities.RoleStaff}
+
+	tests := []struct {
+		name    string
+		actor   entities.Identity
+		current entities.OrderStatus
+		next    entities.OrderStatus
+		allowed bool
+	}{
+		{name: "owner cancels a pending order", actor: owner, current: entities.OrderPending, next: entities.OrderCancelled, allowed: true},
+		{name: "owner cancels a paid order", actor: owner, current: entities.OrderPaid, next: entities.OrderCancelled},
+		{name: "owner cancels a cancelled order, which the lifecycle rejects", actor: owner, current: entities.OrderCancelled, next: entities.OrderCancelled, allowed: true},
+		{name: "owner ships", actor: owner, current: entities.OrderPaid, next: entities.OrderShipped},
+		{name: "owner marks paid", actor: owner, current: entities.OrderPending, next: entities.OrderPaid},
+		{name: "stranger cancels a pending order", actor: stranger, current: entities.OrderPending, next: entities.OrderCancelled},
+		{name: "anonymous cancels a pending order", current: entities.OrderPending, next: entities.OrderCancelled},
+		{name: "staff cancels a pending order", actor: staff, current: entities.OrderPending, next: entities.OrderCancelled, allowed: true},
+		{name: "staff cancels a paid order", actor: staff, current: entities.OrderPaid, next: entities.OrderCancelled, allowed: true},
+		{name: "staff ships", actor: staff, current: entities.OrderPaid, next: entities.OrderShipped, allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Policy{}.ChangeOrderStatus(tt.actor, ownerId, tt.current, tt.next)
+			if tt.allowed && err != nil {
+				t.Fatalf("ChangeOrderStatus = %v, want it allowed", err)
+			}
+			if !tt.allowed && !errors.Is(err, entities.ErrForbidden) {
+				t.Fatalf("ChangeOrderStatus = %v, want ErrForbidden", err)
+			}
+		})
+	}
+}
@@ -1,50 +1,83 @@
 package service
 
 import (
+	"context"
+
 	"github.com/wittawat/go-hex/core/entities"
 	port "github.com/wittawat/go-hex/core/port/product"
 )
 
 type ProductService struct {
-	ob port.ProductOutbound
+	ob        port.ProductOutbound
+	inventory port.InventoryOutbound
+	policy    Policy
 }
 
-func NewProductService(ob port.ProductOutbound) port.ProductInbound {
-	return &ProductService{ob: ob}
+func NewProductService(ob port.ProductOutbound, inventory port.InventoryOutbound) port.ProductInbound {
+	return &ProductService{ob: ob, inventory: inventory}
 }
 
-func (s *ProductService) Save(product *entities.Product) error {
-	if err := s.ob.Save(product); err != nil {
-		return err
+func (s *ProductService) Save(ctx context.Context, product *entities.Product) (int, error) {
+	if err := s.policy.ManageProducts(actorOf(ctx)); err != nil {
+		return 0, err
 	}
-	return nil
+	if err := product.Validate(); err != nil {
+		return 0, err
+	}
+	id, err := s.ob.Save(ctx, product)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
-func (s *ProductService) Find() ([]entities.Product, error) {
-	products, err := s.ob.Find()
+func (s *ProductService) Find(ctx context.Context, query entities.ProductQuery) ([]entities.Product, entities.PageInfo, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	products, page, err := s.ob.Find(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, entities.PageInfo{}, err
 	}
-	return products, nil
+	return products, page, nil
 }
 
-func (s *ProductService) FindById(id int) (*entities.Product, error) {
-	product, err := s.ob.FindById(id)
+func (s *ProductService) FindById(ctx context.Context, id int) (*entities.Product, error) {
+	product, err := s.ob.FindById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return product, nil
 }
 
-func (s *ProductService) UpdateOne(product *entities.Product, id int) error {
-	if err := s.ob.UpdateOne(product, id); err != nil {
+func (s *ProductService) UpdateOne(ctx context.Context, product *entities.Product, id int) error {
+	if err := s.policy.ManageProducts(actorOf(ctx)); err != nil {
+		return err
+	}
+	if err := product.Validate(); err != nil {
+		return err
+	}
+	if err := s.ob.UpdateOne(ctx, product, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ProductService) DeleteOne(ctx context.Context, id int) error {
+	if err := s.policy.ManageProducts(actorOf(ctx)); err != nil {
+		return err
+	}
+	if err := s.ob.DeleteOne(ctx, id); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *ProductService) DeleteOne(id int) error {
-	if err := s.ob.DeleteOne(id); err != nil {
+func (s *ProductService) SetStock(ctx context.Context, id int, stock uint) error {
+	if err := s.policy.ManageProducts(actorOf(ctx)); err != nil {
+		return err
+	}
+	if err := s.inventory.SetStock(ctx, id, stock); err != nil {
 		return err
 	}
 	return nil
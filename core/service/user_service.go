@@ -1,57 +1,200 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
 
 	"github.com/wittawat/go-hex/core/entities"
+	orderPort "github.com/wittawat/go-hex/core/port/order"
+	uowPort "github.com/wittawat/go-hex/core/port/uow"
 	port "github.com/wittawat/go-hex/core/port/user"
 )
 
+var (
+	ErrInvalidPassword = &entities.Error{
+		Kind:    entities.ErrValidation,
+		Message: "invalid password",
+		Fields:  []entities.FieldError{{Field: "password", Message: fmt.Sprintf("must be %d to %d characters", entities.MinPasswordLength, entities.MaxPasswordLength)}},
+	}
+	ErrInvalidCredentials = entities.Unauthorized("invalid email or password")
+)
+
 type UserService struct {
-	ob port.UserOutbound //user repository
+	ob     port.UserOutbound //user repository
+	orders orderPort.OrderRepository
+	hasher port.PasswordHasher
+	// dummyHash is compared against when no user has the email, so that an
+	// unknown email costs as much as a wrong password.
+	dummyHash func() (string, error)
+	uow       uowPort.UnitOfWork
+	policy    Policy
+	logger    *slog.Logger
 }
 
-func NewUserService(ob port.UserOutbound) port.UserInbound {
-	return &UserService{ob: ob}
+func NewUserService(ob port.UserOutbound, orders orderPort.OrderRepository, hasher port.PasswordHasher, uow uowPort.UnitOfWork, logger *slog.Logger) port.UserInbound {
+	dummyHash := sync.OnceValues(func() (string, error) {
+		return hasher.Hash(context.Background(), "no user has this password")
+	})
+	return &UserService{ob: ob, orders: orders, hasher: hasher, dummyHash: dummyHash, uow: uow, logger: logger}
 }
 
-func (s *UserService) Save(user *entities.User) error {
-	if len(user.Password) < 4 {
-		return errors.New("invalid password")
+func (s *UserService) Save(ctx context.Context, user *entities.User) (int, error) {
+	user.Role = entities.RoleCustomer
+	if err := user.Validate(); err != nil {
+		return 0, err
+	}
+	if err := s.hashPassword(ctx, user); err != nil {
+		return 0, err
 	}
 
-	if err := s.ob.Save(user); err != nil {
-		return err
+	id, err := s.ob.Save(ctx, user)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+	return id, nil
 }
 
-func (s *UserService) FindById(id int) (*entities.User, error) {
-	user, err := s.ob.FindById(id)
+func (s *UserService) FindById(ctx context.Context, id int) (*entities.User, error) {
+	if err := s.policy.AccessUser(actorOf(ctx), id); err != nil {
+		return nil, err
+	}
+	user, err := s.ob.FindById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (s *UserService) Find() ([]entities.User, error) {
-	users, err := s.ob.Find()
+func (s *UserService) Find(ctx context.Context, query entities.UserQuery) ([]entities.User, entities.PageInfo, error) {
+	if err := s.policy.ListUsers(actorOf(ctx)); err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	if err := query.Normalize(); err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	users, page, err := s.ob.Find(ctx, query)
+	if err != nil {
+		return nil, entities.PageInfo{}, err
+	}
+	return users, page, nil
+}
+
+// UpdateOne keeps the stored password hash when user.Password is empty and the
+// stored role when user.Role is empty. Only admins may change a role.
+func (s *UserService) UpdateOne(ctx context.Context, user *entities.User, id int) error {
+	actor := actorOf(ctx)
+	if err := s.policy.AccessUser(actor, id); err != nil {
+		return err
+	}
+	existUser, err := s.ob.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == "" {
+		user.Role = existUser.Role
+	} else if user.Role != existUser.Role {
+		if err := s.policy.ChangeRole(actor); err != nil {
+			return err
+		}
+	}
+	if err := user.Validate(); err != nil {
+		return err
+	}
+
+	if user.Password == "" {
+		user.Password = existUser.Password
+	} else if err := s.hashPassword(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.ob.UpdateOne(ctx, user, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *UserService) DeleteOne(ctx context.Context, id int) error {
+	if err := s.policy.DeleteUser(actorOf(ctx)); err != nil {
+		return err
+	}
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		// Orders hold reserved stock and payments, so a user who has any is
+		// kept; the database refuses to drop their orders as well.
+		orders, err := s.orders.FindByUserId(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(orders) > 0 {
+			return entities.Conflict("user %d has orders and cannot be deleted", id)
+		}
+		return s.ob.DeleteOne(ctx, id)
+	})
+}
+
+func (s *UserService) VerifyCredentials(ctx context.Context, email, password string) (*entities.User, error) {
+	user, err := s.ob.FindByEmail(ctx, email)
+	if errors.Is(err, entities.ErrNotFound) {
+		if hash, err := s.dummyHash(); err == nil {
+			_ = s.hasher.Compare(ctx, hash, password)
+		}
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
 		return nil, err
 	}
-	return users, nil
+	if err := s.hasher.Compare(ctx, user.Password, password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
 }
 
-func (s *UserService) UpdateOne(user *entities.User, id int) error {
-	if err := s.ob.UpdateOne(user, id); err != nil {
+func (s *UserService) EnsureAdmin(ctx context.Context, username, email, password string) error {
+	user := entities.User{Username: username, Email: email, Password: password, Role: entities.RoleAdmin}
+	if err := user.Validate(); err != nil {
+		return err
+	}
+	if err := s.hashPassword(ctx, &user); err != nil {
 		return err
 	}
+
+	existUser, err := s.ob.FindByEmail(ctx, email)
+	if errors.Is(err, entities.ErrNotFound) {
+		if _, err := s.ob.Save(ctx, &user); err != nil {
+			return err
+		}
+		s.logger.InfoContext(ctx, "admin account created", "user", user)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existUser.Role == entities.RoleAdmin {
+		return nil
+	}
+	// Registration is open, so whoever signed up with the address first must
+	// not keep a password of their own choosing once they are an admin.
+	existUser.Role = entities.RoleAdmin
+	existUser.Password = user.Password
+	if err := s.ob.UpdateOne(ctx, existUser, existUser.Id); err != nil {
+		return err
+	}
+	s.logger.InfoContext(ctx, "user promoted to admin", "user", existUser)
 	return nil
 }
 
-func (s *UserService) DeleteOne(id int) error {
-	if err := s.ob.DeleteOne(id); err != nil {
+// hashPassword replaces the plaintext password on user with its hash.
+func (s *UserService) hashPassword(ctx context.Context, user *entities.User) error {
+	if len(user.Password) < entities.MinPasswordLength || len(user.Password) > entities.MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	hash, err := s.hasher.Hash(ctx, user.Password)
+	if err != nil {
 		return err
 	}
+	user.Password = hash
 	return nil
 }
@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wittawat/go-hex/adapter/memtx"
+	orderAdapter "github.com/wittawat/go-hex/adapter/order"
+	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/core/entities"
+	userPort "github.com/wittawat/go-hex/core/port/user"
+	"github.com/wittawat/go-hex/core/service"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var admin = entities.Identity{UserId: 3, Email: "carol@example.com", Role: entities.RoleAdmin}
+
+// userFixture is a UserService sharing the order repository and unit of work
+// of an orderFixture.
+type userFixture struct {
+	*orderFixture
+	users     userPort.UserInbound
+	userStore *userAdapter.MemoryUserRepository
+}
+
+func newUserFixture(t *testing.T) *userFixture {
+	t.Helper()
+	f := &userFixture{orderFixture: newOrderFixture(t), userStore: userAdapter.NewMemoryUserRepository()}
+	f.users = service.NewUserService(f.userStore, f.orders, userAdapter.NewBcryptPasswordHasher(bcrypt.MinCost), f.uow, slog.New(slog.DiscardHandler))
+	return f
+}
+
+func (f *userFixture) register(t *testing.T, username, email, password string) int {
+	t.Helper()
+	id, err := f.users.Save(t.Context(), &entities.User{Username: username, Email: email, Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestUserServiceDeleteOneKeepsUsersWithOrders(t *testing.T) {
+	f := newUserFixture(t)
+	id := f.register(t, "alice", customer.Email, "correct horse")
+	if id != customer.UserId {
+		t.Fatalf("registered alice as user %d, want %d", id, customer.UserId)
+	}
+	keyboard := f.seedProduct(t, "keyboard", 129000, 5)
+	orderId := f.placeOrder(t, keyboard, 2)
+
+	if err := f.users.DeleteOne(as(t, admin), id); !errors.Is(err, entities.ErrConflict) {
+		t.Fatalf("DeleteOne of a user with an order = %v, want ErrConflict", err)
+	}
+	if _, err := f.userStore.FindById(t.Context(), id); err != nil {
+		t.Fatalf("user after refused DeleteOne: %v", err)
+	}
+	f.assertStatus(t, orderId, entities.OrderPending)
+	f.assertStock(t, keyboard, 3)
+
+	other := f.register(t, "dave", "dave@example.com", "correct horse")
+	if err := f.users.DeleteOne(as(t, admin), other); err != nil {
+		t.Fatalf("DeleteOne of a user without orders: %v", err)
+	}
+}
+
+func TestUserServiceEnsureAdminResetsPasswordOnPromotion(t *testing.T) {
+	f := newUserFixture(t)
+	id := f.register(t, "mallory", "admin@example.com", "mallory's own")
+
+	if err := f.users.EnsureAdmin(t.Context(), "admin", "admin@example.com", "configured secret"); err != nil {
+		t.Fatal(err)
+	}
+	user, err := f.userStore.FindById(t.Context(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != entities.RoleAdmin {
+		t.Errorf("role = %q, want %q", user.Role, entities.RoleAdmin)
+	}
+	if _, err := f.users.VerifyCredentials(t.Context(), "admin@example.com", "mallory's own"); !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Errorf("VerifyCredentials with the registered password = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := f.users.VerifyCredentials(t.Context(), "admin@example.com", "configured secret"); err != nil {
+		t.Errorf("VerifyCredentials with the configured password: %v", err)
+	}
+}
+
+// countingHasher counts the comparisons made through it.
+type countingHasher struct {
+	userPort.PasswordHasher
+	compares atomic.Int32
+}
+
+func (h *countingHasher) Compare(ctx context.Context, hash, password string) error {
+	h.compares.Add(1)
+	return h.PasswordHasher.Compare(ctx, hash, password)
+}
+
+func TestUserServiceVerifyCredentialsComparesForUnknownEmail(t *testing.T) {
+	hasher := &countingHasher{PasswordHasher: userAdapter.NewBcryptPasswordHasher(bcrypt.MinCost)}
+	users := service.NewUserService(userAdapter.NewMemoryUserRepository(), orderAdapter.NewMemoryOrderRepository(), hasher, memtx.NewUnitOfWork(), slog.New(slog.DiscardHandler))
+
+	for range 2 {
+		if _, err := users.VerifyCredentials(t.Context(), "nobody@example.com", "correct horse"); !errors.Is(err, service.ErrInvalidCredentials) {
+			t.Fatalf("VerifyCredentials of an unknown email = %v, want ErrInvalidCredentials", err)
+		}
+	}
+	if got := hasher.compares.Load(); got != 2 {
+		t.Errorf("hasher compared %d times, want once per attempt", got)
+	}
+}
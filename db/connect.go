@@ -1,22 +1,74 @@
 package db
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/wittawat/go-hex/config"
 )
 
-func InitializeMysqlDB(dbName string) (*sql.DB, error) {
-	log.Println("Connect to mysqldb...")
-	db, err := sql.Open(dbName, "root:1234@tcp(127.0.0.1:3306)/mydb")
+func InitializeMysqlDB(cfg config.MySQLConfig, pool config.PoolConfig, logger *slog.Logger) (*sql.DB, error) {
+	logger.Info("connect to mysql", "host", cfg.Host, "port", cfg.Port, "database", cfg.Database)
+	db, err := sql.Open("mysql", cfg.DSN())
 	if err != nil {
 		return nil, err
 	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 	return db, nil
 }
 
-func DisconnectMysqlDB(db *sql.DB) error {
+// WaitForDB pings db until it answers, sleeping between attempts with an
+// exponential backoff. sql.Open does not connect, so this is the first point a
+// wrong address or credentials show up.
+func WaitForDB(ctx context.Context, db *sql.DB, cfg config.ConnectConfig, logger *slog.Logger) error {
+	backoff := cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx, db, cfg.PingTimeout)
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.Attempts {
+			return fmt.Errorf("database did not answer after %d attempts: %w", attempt, err)
+		}
+		logger.Warn("ping database failed", "attempt", attempt, "attempts", cfg.Attempts, "retry_in", backoff.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, cfg.MaxBackoff)
+	}
+}
+
+func ping(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return db.PingContext(ctx)
+}
+
+// DisconnectDB closes the pool once nothing uses it any more.
+func DisconnectDB(db *sql.DB) error {
 	if err := db.Close(); err != nil {
 		return err
 	}
 	return nil
 }
+
+// InitializeSqliteDB opens the SQLite database at cfg.Path, or a private one when the path is ":memory:".
+func InitializeSqliteDB(cfg config.SQLiteConfig, logger *slog.Logger) (*sql.DB, error) {
+	logger.Info("open sqlite", "path", cfg.Path)
+	db, err := sql.Open("sqlite", cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer, and every connection to ":memory:" would get its own database.
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
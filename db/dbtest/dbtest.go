@@ -0,0 +1,153 @@
+// Package dbtest opens migrated, empty databases for the SQL repository tests.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/wittawat/go-hex/config"
+	"github.com/wittawat/go-hex/db"
+	"github.com/wittawat/go-hex/db/migrations"
+	_ "modernc.org/sqlite"
+)
+
+// MySQLEnv names the database the MySQL variants run against. The tests empty
+// it, so point it at a database of its own, e.g.
+// "gohex:secret@tcp(127.0.0.1:3306)/gohex_test".
+const MySQLEnv = "TEST_MYSQL_DSN"
+
+// lockName keeps the test binaries of several packages, which go test runs in
+// parallel, from emptying the MySQL database under each other.
+const lockName = "go-hex.dbtest"
+
+// Dialect is one database the SQL repositories are tested on.
+type Dialect struct {
+	// Name is config.StorageMySQL or config.StorageSQLite.
+	Name string
+	// Open returns a migrated database holding no rows, so that generated ids
+	// start from 1. It skips t when the database is not available.
+	Open func(t testing.TB) *sql.DB
+}
+
+// Dialects lists every database the SQL repositories must work on.
+var Dialects = []Dialect{
+	{Name: config.StorageSQLite, Open: SQLite},
+	{Name: config.StorageMySQL, Open: MySQL},
+}
+
+// SQLite returns a fresh database in a temporary file.
+func SQLite(t testing.TB) *sql.DB {
+	t.Helper()
+	conn, err := db.InitializeSqliteDB(config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "test.db")}, discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	migrate(t, conn, config.StorageSQLite)
+	return conn
+}
+
+// MySQL returns the database named by TEST_MYSQL_DSN after deleting every row
+// and resetting the auto-increment counters. It holds a lock on the database
+// until t finishes.
+func MySQL(t testing.TB) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(MySQLEnv)
+	if dsn == "" {
+		t.Skip(MySQLEnv + " is not set")
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("%s: %v", MySQLEnv, err)
+	}
+	// the repositories rely on both, see config.MySQLConfig.DSN
+	cfg.ParseTime = true
+	cfg.ClientFoundRows = true
+
+	conn, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ctx := context.Background()
+	lock, err := conn.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var acquired sql.NullInt64
+	if err := lock.QueryRowContext(ctx, "SELECT GET_LOCK(?, 60)", lockName).Scan(&acquired); err != nil || acquired.Int64 != 1 {
+		t.Fatalf("lock %s: %v", lockName, err)
+	}
+	// registered after conn.Close, so it runs before it
+	t.Cleanup(func() {
+		lock.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", lockName).Scan(&acquired)
+		lock.Close()
+	})
+
+	migrate(t, conn, config.StorageMySQL)
+	truncate(t, ctx, lock)
+	return conn
+}
+
+func migrate(t testing.TB, conn *sql.DB, dialect string) {
+	t.Helper()
+	migrator, err := db.NewMigrator(conn, dialect, sourceOf(dialect), discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// truncate empties every table but the migration bookkeeping. TRUNCATE refuses
+// tables that foreign keys point at, so the checks are off meanwhile; the
+// setting only applies to conn's session.
+func truncate(t testing.TB, ctx context.Context, conn *sql.Conn) {
+	t.Helper()
+	rows, err := conn.QueryContext(ctx, "SELECT table_name FROM information_schema.tables "+
+		"WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' AND table_name <> 'schema_migrations'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 1")
+	for _, table := range tables {
+		if _, err := conn.ExecContext(ctx, "TRUNCATE TABLE `"+table+"`"); err != nil {
+			t.Fatalf("truncate %s: %v", table, err)
+		}
+	}
+}
+
+func sourceOf(dialect string) fs.FS {
+	if dialect == config.StorageMySQL {
+		return migrations.MySQL()
+	}
+	return migrations.SQLite()
+}
+
+func discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
@@ -0,0 +1,324 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wittawat/go-hex/config"
+)
+
+const migrationTable = "schema_migrations"
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// lockName is the MySQL named lock held while migrating, and lockTimeout how
+// long a migrator waits for another instance to release it.
+const (
+	lockName    = "go-hex." + migrationTable
+	lockTimeout = time.Minute
+)
+
+// queryer is the pool for Status and the locked connection for Up and Down.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// NewMigrator loads every "<version>_<name>.up.sql" / ".down.sql" pair from
+// source. dialect is config.StorageMySQL or config.StorageSQLite and decides
+// how migrators of instances sharing the database keep out of each other's way.
+func NewMigrator(db *sql.DB, dialect string, source fs.FS, logger *slog.Logger) (*Migrator, error) {
+	if dialect != config.StorageMySQL && dialect != config.StorageSQLite {
+		return nil, fmt.Errorf("cannot migrate %s storage", dialect)
+	}
+	migrations, err := loadMigrations(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations, logger: logger}, nil
+}
+
+// Up applies every pending migration in version order. Each one re-reads the
+// applied versions under the lock, so instances started together apply it once.
+func (m *Migrator) Up() ([]Migration, error) {
+	var done []Migration
+	err := m.locked(func(ctx context.Context, conn *sql.Conn) error {
+		for _, migration := range m.migrations {
+			ran := false
+			err := m.inTx(ctx, conn, func() error {
+				applied, err := appliedVersions(ctx, conn)
+				if err != nil {
+					return err
+				}
+				if _, ok := applied[migration.Version]; ok {
+					return nil
+				}
+				m.logger.Info("apply migration", "version", migration.Version, "name", migration.Name)
+				if err := run(ctx, conn, migration.Up); err != nil {
+					return err
+				}
+				query := "INSERT INTO " + migrationTable + " (version, name, applied_at) VALUES (?, ?, ?)"
+				_, err = conn.ExecContext(ctx, query, migration.Version, migration.Name, time.Now().UTC())
+				ran = true
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Name, err)
+			}
+			if ran {
+				done = append(done, migration)
+			}
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the most recently applied migration. It returns nil when nothing is applied.
+func (m *Migrator) Down() (*Migration, error) {
+	var reverted *Migration
+	err := m.locked(func(ctx context.Context, conn *sql.Conn) error {
+		return m.inTx(ctx, conn, func() error {
+			applied, err := appliedVersions(ctx, conn)
+			if err != nil {
+				return err
+			}
+			for i := len(m.migrations) - 1; i >= 0; i-- {
+				migration := m.migrations[i]
+				if _, ok := applied[migration.Version]; !ok {
+					continue
+				}
+				m.logger.Info("revert migration", "version", migration.Version, "name", migration.Name)
+				if err := run(ctx, conn, migration.Down); err != nil {
+					return fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Name, err)
+				}
+				if _, err := conn.ExecContext(ctx, "DELETE FROM "+migrationTable+" WHERE version=?", migration.Version); err != nil {
+					return fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Name, err)
+				}
+				reverted = &migration
+				return nil
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reverted, nil
+}
+
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := appliedVersions(context.Background(), m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// locked runs fn on a single connection. On MySQL that connection holds a
+// named lock for the whole run, because DDL commits implicitly and a
+// transaction cannot keep two migrators apart.
+func (m *Migrator) locked(fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect == config.StorageMySQL {
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(lockTimeout.Seconds())).Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired.Int64 != 1 {
+			return fmt.Errorf("another migrator held lock %q for over %s", lockName, lockTimeout)
+		}
+		defer conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", lockName).Scan(&acquired)
+	}
+	return fn(ctx, conn)
+}
+
+// inTx runs fn inside a transaction opened with plain statements on conn, so
+// that SQLite can begin it IMMEDIATE: the write lock is then taken before fn
+// reads schema_migrations, and a second migrator waits until the first commits.
+// MySQL auto-commits DDL statements, so there the transaction only guards the
+// bookkeeping and the named lock does the rest.
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, fn func() error) error {
+	begin := "START TRANSACTION"
+	if m.dialect == config.StorageSQLite {
+		begin = "BEGIN IMMEDIATE"
+	}
+	if _, err := conn.ExecContext(ctx, begin); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		conn.ExecContext(ctx, "ROLLBACK")
+		return err
+	}
+	_, err := conn.ExecContext(ctx, "COMMIT")
+	return err
+}
+
+// run executes the statements of one migration script.
+func run(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, statement := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ensureTable(ctx context.Context, q queryer) error {
+	query := "CREATE TABLE IF NOT EXISTS " + migrationTable + ` (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`
+	_, err := q.ExecContext(ctx, query)
+	return err
+}
+
+func appliedVersions(ctx context.Context, q queryer) (map[int64]time.Time, error) {
+	if err := ensureTable(ctx, q); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM "+migrationTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func loadMigrations(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		version, name, direction, err := parseMigrationName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func parseMigrationName(filename string) (int64, string, string, error) {
+	base := strings.TrimSuffix(filename, ".sql")
+	direction := path.Ext(base)
+	if direction != ".up" && direction != ".down" {
+		return 0, "", "", fmt.Errorf("migration %q must end with .up.sql or .down.sql", filename)
+	}
+	base = strings.TrimSuffix(base, direction)
+
+	prefix, name, ok := strings.Cut(base, "_")
+	if !ok || name == "" {
+		return 0, "", "", fmt.Errorf("migration %q must be named <version>_<name>", filename)
+	}
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("migration %q has an invalid version", filename)
+	}
+	return version, name, direction[1:], nil
+}
+
+// splitStatements breaks a script into single statements, since the driver
+// does not accept several statements in one Exec. Comment lines are dropped
+// first, so a semicolon inside a comment does not end a statement.
+func splitStatements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var statements []string
+	for _, part := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if statement := strings.TrimSpace(part); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
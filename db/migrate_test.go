@@ -0,0 +1,287 @@
+package db
+
+import (
+	"database/sql"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/wittawat/go-hex/config"
+	"github.com/wittawat/go-hex/db/migrations"
+	_ "modernc.org/sqlite"
+)
+
+func TestParseMigrationName(t *testing.T) {
+	tests := []struct {
+		filename  string
+		version   int64
+		name      string
+		direction string
+		wantErr   bool
+	}{
+		{filename: "0001_create_users_table.up.sql", version: 1, name: "create_users_table", direction: "up"},
+		{filename: "0012_add_index.down.sql", version: 12, name: "add_index", direction: "down"},
+		{filename: "20240101_a_b_c.up.sql", version: 20240101, name: "a_b_c", direction: "up"},
+		{filename: "0001_create_users_table.sql", wantErr: true},
+		{filename: "0001_create_users_table.sideways.sql", wantErr: true},
+		{filename: "0001.up.sql", wantErr: true},
+		{filename: "0001_.up.sql", wantErr: true},
+		{filename: "one_create_users_table.up.sql", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			version, name, direction, err := parseMigrationName(tt.filename)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseMigrationName(%q) = %d, %q, %q, want an error", tt.filename, version, name, direction)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMigrationName(%q): %v", tt.filename, err)
+			}
+			if version != tt.version || name != tt.name || direction != tt.direction {
+				t.Errorf("parseMigrationName(%q) = %d, %q, %q, want %d, %q, %q", tt.filename, version, name, direction, tt.version, tt.name, tt.direction)
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{name: "empty", script: "", want: nil},
+		{name: "single without semicolon", script: "CREATE TABLE a (id INT)", want: []string{"CREATE TABLE a (id INT)"}},
+		{
+			name:   "several",
+			script: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "multi-line statement",
+			script: "CREATE TABLE a (\n  id INT,\n  name TEXT\n);",
+			want:   []string{"CREATE TABLE a (\n  id INT,\n  name TEXT\n)"},
+		},
+		{
+			name:   "comment with semicolon",
+			script: "-- drop a; then b\nDROP TABLE a;\n  -- indented; comment\nDROP TABLE b;",
+			want:   []string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{name: "blank statements", script: ";;\n;", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	t.Run("sorted by version", func(t *testing.T) {
+		source := fstest.MapFS{
+			"0010_second.up.sql":   file("up 10"),
+			"0010_second.down.sql": file("down 10"),
+			"0002_first.up.sql":    file("up 2"),
+			"README.md":            file("ignored"),
+			"nested/0001_x.up.sql": file("ignored"),
+		}
+		got, err := loadMigrations(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []Migration{
+			{Version: 2, Name: "first", Up: "up 2"},
+			{Version: 10, Name: "second", Up: "up 10", Down: "down 10"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("loadMigrations = %+v, want %+v", got, want)
+		}
+	})
+
+	failures := []struct {
+		name   string
+		source fstest.MapFS
+		want   string
+	}{
+		{
+			name:   "down without up",
+			source: fstest.MapFS{"0001_a.down.sql": file("down")},
+			want:   "has no up script",
+		},
+		{
+			name:   "conflicting names",
+			source: fstest.MapFS{"0001_a.up.sql": file("up"), "0001_b.down.sql": file("down")},
+			want:   "conflicting names",
+		},
+		{
+			name:   "bad file name",
+			source: fstest.MapFS{"users.up.sql": file("up")},
+			want:   "must be named",
+		},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadMigrations(tt.source); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadMigrations error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratorUpDownSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migrate.db")
+	migrator := newSqliteMigrator(t, openSqlite(t, path))
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrator.migrations) {
+		t.Fatalf("Up applied %d migrations, want %d", len(applied), len(migrator.migrations))
+	}
+	if applied, err := migrator.Up(); err != nil || len(applied) != 0 {
+		t.Fatalf("second Up = %d migrations, %v, want none", len(applied), err)
+	}
+	assertApplied(t, migrator, len(migrator.migrations))
+
+	for i := len(migrator.migrations) - 1; i >= 0; i-- {
+		reverted, err := migrator.Down()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reverted == nil || reverted.Version != migrator.migrations[i].Version {
+			t.Fatalf("Down reverted %v, want version %d", reverted, migrator.migrations[i].Version)
+		}
+	}
+	if reverted, err := migrator.Down(); err != nil || reverted != nil {
+		t.Fatalf("Down with nothing applied = %v, %v, want nil, nil", reverted, err)
+	}
+	assertApplied(t, migrator, 0)
+
+	// the down scripts must leave a schema the up scripts can build on again
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	assertApplied(t, migrator, len(migrator.migrations))
+}
+
+func TestMigratorUpConcurrentSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migrate.db")
+
+	// each instance gets its own pool, as separate processes would
+	const instances = 4
+	migrators := make([]*Migrator, instances)
+	for i := range migrators {
+		migrators[i] = newSqliteMigrator(t, openSqlite(t, path))
+	}
+
+	var wg sync.WaitGroup
+	counts := make([]int, instances)
+	errs := make([]error, instances)
+	for i, migrator := range migrators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			applied, err := migrator.Up()
+			counts[i], errs[i] = len(applied), err
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for i := range migrators {
+		if errs[i] != nil {
+			t.Errorf("instance %d: %v", i, errs[i])
+		}
+		total += counts[i]
+	}
+	if want := len(migrators[0].migrations); total != want {
+		t.Errorf("instances applied %d migrations between them, want each of the %d once", total, want)
+	}
+	assertApplied(t, migrators[0], len(migrators[0].migrations))
+}
+
+// TestMigratorUpConcurrentMySQL runs against the database named by
+// TEST_MYSQL_DSN, which it leaves migrated; the DSN needs parseTime=true.
+func TestMigratorUpConcurrentMySQL(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+
+	const instances = 4
+	var wg sync.WaitGroup
+	errs := make([]error, instances)
+	for i := range instances {
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		migrator, err := NewMigrator(db, config.StorageMySQL, migrations.MySQL(), slog.New(slog.DiscardHandler))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = migrator.Up()
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("instance %d: %v", i, err)
+		}
+	}
+}
+
+func openSqlite(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := InitializeSqliteDB(config.SQLiteConfig{Path: path}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newSqliteMigrator(t *testing.T, db *sql.DB) *Migrator {
+	t.Helper()
+	migrator, err := NewMigrator(db, config.StorageSQLite, migrations.SQLite(), slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator
+}
+
+func assertApplied(t *testing.T, migrator *Migrator, want int) {
+	t.Helper()
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := 0
+	for _, status := range statuses {
+		if status.Applied {
+			applied++
+		}
+	}
+	if applied != want {
+		t.Errorf("Status reports %d applied migrations, want %d", applied, want)
+	}
+}
@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// MySQL returns the migration files for the MySQL schema.
+func MySQL() fs.FS {
+	return sub("mysql")
+}
+
+// SQLite returns the same schema as MySQL written in the SQLite dialect.
+func SQLite() fs.FS {
+	return sub("sqlite")
+}
+
+func sub(dir string) fs.FS {
+	dialect, err := fs.Sub(files, dir)
+	if err != nil {
+		panic(err)
+	}
+	return dialect
+}
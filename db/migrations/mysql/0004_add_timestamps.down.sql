@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN created_at, DROP COLUMN updated_at;
+ALTER TABLE products DROP COLUMN created_at, DROP COLUMN updated_at;
+ALTER TABLE users DROP COLUMN created_at, DROP COLUMN updated_at;
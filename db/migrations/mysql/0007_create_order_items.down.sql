@@ -0,0 +1,12 @@
+-- Orders keep only their first item, and orders without items are dropped.
+DELETE FROM orders WHERE NOT EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = orders.id);
+
+ALTER TABLE orders ADD COLUMN product_id INT UNSIGNED NOT NULL DEFAULT 0 AFTER user_id;
+UPDATE orders o SET product_id = (SELECT i.product_id FROM order_items i WHERE i.order_id = o.id ORDER BY i.id LIMIT 1);
+ALTER TABLE orders
+    ALTER COLUMN product_id DROP DEFAULT,
+    ADD CONSTRAINT fk_orders_product FOREIGN KEY (product_id) REFERENCES products (id),
+    DROP COLUMN status,
+    DROP COLUMN total;
+
+DROP TABLE order_items;
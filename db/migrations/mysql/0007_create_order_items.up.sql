@@ -0,0 +1,27 @@
+-- An order becomes a header with a status and a total, and its products move
+-- to line items. Every existing order turns into one pending item of quantity 1
+-- at the product's current price.
+CREATE TABLE order_items (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    order_id INT UNSIGNED NOT NULL,
+    product_id INT UNSIGNED NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    quantity INT UNSIGNED NOT NULL,
+    unit_price INT UNSIGNED NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_order_items_order_id (order_id),
+    CONSTRAINT fk_order_items_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_items_product FOREIGN KEY (product_id) REFERENCES products (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO order_items (order_id, product_id, title, quantity, unit_price)
+SELECT o.id, o.product_id, p.title, 1, p.price FROM orders o JOIN products p ON p.id = o.product_id;
+
+ALTER TABLE orders
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    ADD COLUMN total INT UNSIGNED NOT NULL DEFAULT 0;
+
+UPDATE orders o SET total = (SELECT COALESCE(SUM(i.quantity * i.unit_price), 0) FROM order_items i WHERE i.order_id = o.id);
+
+ALTER TABLE orders DROP FOREIGN KEY fk_orders_product;
+ALTER TABLE orders DROP COLUMN product_id;
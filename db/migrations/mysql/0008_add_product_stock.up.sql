@@ -0,0 +1,2 @@
+-- Existing products start out of stock until staff set their stock.
+ALTER TABLE products ADD COLUMN stock INT UNSIGNED NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+CREATE TABLE payments (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    order_id INT UNSIGNED NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    amount INT UNSIGNED NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_payments_reference (reference),
+    KEY idx_payments_order_id (order_id),
+    CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,20 @@
+-- Amounts are truncated back to whole units and their currency is dropped.
+UPDATE payments SET amount = amount DIV 100;
+ALTER TABLE payments
+    DROP COLUMN currency,
+    MODIFY COLUMN amount INT UNSIGNED NOT NULL;
+
+UPDATE order_items SET unit_price = unit_price DIV 100;
+ALTER TABLE order_items MODIFY COLUMN unit_price INT UNSIGNED NOT NULL;
+
+UPDATE orders SET total = total DIV 100;
+ALTER TABLE orders
+    DROP COLUMN currency,
+    MODIFY COLUMN total INT UNSIGNED NOT NULL DEFAULT 0;
+
+DROP INDEX idx_products_currency_price ON products;
+UPDATE products SET price = price DIV 100;
+ALTER TABLE products
+    DROP COLUMN currency,
+    MODIFY COLUMN price INT UNSIGNED NOT NULL DEFAULT 0;
+CREATE INDEX idx_products_price ON products (price);
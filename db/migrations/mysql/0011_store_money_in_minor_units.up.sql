@@ -0,0 +1,22 @@
+-- Amounts move from whole baht to minor units (satang, cents) and carry their
+-- currency. Everything stored so far was priced in THB. Order items take the
+-- currency of their order.
+ALTER TABLE products
+    MODIFY COLUMN price BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB' AFTER price;
+UPDATE products SET price = price * 100;
+DROP INDEX idx_products_price ON products;
+CREATE INDEX idx_products_currency_price ON products (currency, price);
+
+ALTER TABLE orders
+    MODIFY COLUMN total BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB' AFTER total;
+UPDATE orders SET total = total * 100;
+
+ALTER TABLE order_items MODIFY COLUMN unit_price BIGINT UNSIGNED NOT NULL;
+UPDATE order_items SET unit_price = unit_price * 100;
+
+ALTER TABLE payments
+    MODIFY COLUMN amount BIGINT UNSIGNED NOT NULL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB' AFTER amount;
+UPDATE payments SET amount = amount * 100;
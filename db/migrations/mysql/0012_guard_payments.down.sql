@@ -0,0 +1,4 @@
+ALTER TABLE payments
+    DROP COLUMN refund_due,
+    DROP INDEX uq_payments_pending_order,
+    DROP COLUMN pending_order_id;
@@ -0,0 +1,15 @@
+-- An order has at most one payment awaiting the gateway, so two attempts to
+-- pay for it cannot both charge. MySQL has no partial indexes, so the unique
+-- key is on a column that is NULL unless the payment is pending. Should a race
+-- have left several, the latest is kept. refund_due marks an authorised
+-- payment whose order was cancelled.
+UPDATE payments
+JOIN (SELECT order_id, MAX(id) AS latest FROM payments WHERE status = 'pending' GROUP BY order_id) AS pending
+    ON pending.order_id = payments.order_id
+SET payments.status = 'declined', payments.reason = 'superseded by a later attempt'
+WHERE payments.status = 'pending' AND payments.id < pending.latest;
+
+ALTER TABLE payments
+    ADD COLUMN pending_order_id INT UNSIGNED AS (CASE WHEN status = 'pending' THEN order_id END) VIRTUAL,
+    ADD UNIQUE KEY uq_payments_pending_order (pending_order_id),
+    ADD COLUMN refund_due BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP FOREIGN KEY fk_payments_order;
+ALTER TABLE payments ADD CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+-- A payment is a record of money taken or refused, so it outlives any attempt
+-- to delete its order: the order is refused instead.
+ALTER TABLE payments DROP FOREIGN KEY fk_payments_order;
+ALTER TABLE payments ADD CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE RESTRICT;
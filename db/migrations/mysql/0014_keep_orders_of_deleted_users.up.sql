@@ -0,0 +1,4 @@
+-- Deleting a user must not take their orders with it: that would drop
+-- reserved stock and the payments taken for them. The user is refused instead.
+ALTER TABLE orders DROP FOREIGN KEY fk_orders_user;
+ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT;
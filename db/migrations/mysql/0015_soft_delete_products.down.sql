@@ -0,0 +1,2 @@
+-- Products deleted since the upgrade become visible again.
+ALTER TABLE products DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+-- Order items keep pointing at the products they were bought as, so a deleted
+-- product is marked rather than removed.
+ALTER TABLE products ADD COLUMN deleted_at DATETIME(6) NULL;
@@ -0,0 +1,6 @@
+CREATE TABLE products (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    price INTEGER NOT NULL DEFAULT 0 CHECK (price >= 0),
+    detail TEXT NOT NULL
+);
@@ -0,0 +1,9 @@
+CREATE TABLE orders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_orders_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+
+CREATE INDEX idx_orders_user_id ON orders (user_id);
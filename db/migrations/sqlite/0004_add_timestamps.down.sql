@@ -0,0 +1,6 @@
+ALTER TABLE orders DROP COLUMN created_at;
+ALTER TABLE orders DROP COLUMN updated_at;
+ALTER TABLE products DROP COLUMN created_at;
+ALTER TABLE products DROP COLUMN updated_at;
+ALTER TABLE users DROP COLUMN created_at;
+ALTER TABLE users DROP COLUMN updated_at;
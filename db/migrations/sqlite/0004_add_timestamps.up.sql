@@ -0,0 +1,12 @@
+-- SQLite cannot add a column with a non-constant default, so existing rows are stamped afterwards.
+ALTER TABLE users ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE users ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE users SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+ALTER TABLE products ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE products ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE products SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+ALTER TABLE orders ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE orders ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE orders SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
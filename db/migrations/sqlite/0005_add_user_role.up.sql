@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'customer';
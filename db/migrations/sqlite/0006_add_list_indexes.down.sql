@@ -0,0 +1,3 @@
+DROP INDEX idx_users_created_at;
+DROP INDEX idx_products_created_at;
+DROP INDEX idx_products_price;
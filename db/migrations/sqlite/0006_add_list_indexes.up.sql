@@ -0,0 +1,4 @@
+-- columns that GET /products and GET /users sort and filter on
+CREATE INDEX idx_products_price ON products (price);
+CREATE INDEX idx_products_created_at ON products (created_at);
+CREATE INDEX idx_users_created_at ON users (created_at);
@@ -0,0 +1,18 @@
+-- Orders keep only their first item, and orders without items are dropped.
+CREATE TABLE orders_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00',
+    updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00',
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_orders_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+INSERT INTO orders_old (id, user_id, product_id, created_at, updated_at)
+SELECT o.id, o.user_id, (SELECT i.product_id FROM order_items i WHERE i.order_id = o.id ORDER BY i.id LIMIT 1), o.created_at, o.updated_at
+FROM orders o WHERE EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id);
+
+DROP TABLE order_items;
+DROP TABLE orders;
+ALTER TABLE orders_old RENAME TO orders;
+CREATE INDEX idx_orders_user_id ON orders (user_id);
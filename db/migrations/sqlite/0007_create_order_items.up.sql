@@ -0,0 +1,36 @@
+-- An order becomes a header with a status and a total, and its products move
+-- to line items. Every existing order turns into one pending item of quantity 1
+-- at the product's current price.
+-- SQLite cannot drop a column that is part of a foreign key, so orders is rebuilt.
+CREATE TABLE orders_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    total INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+INSERT INTO orders_new (id, user_id, created_at, updated_at) SELECT id, user_id, created_at, updated_at FROM orders;
+
+-- created against orders_new so that dropping the old table cannot cascade into it, and
+-- the rename below points the reference at orders
+CREATE TABLE order_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price INTEGER NOT NULL,
+    CONSTRAINT fk_order_items_order FOREIGN KEY (order_id) REFERENCES orders_new (id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_items_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+
+INSERT INTO order_items (order_id, product_id, title, quantity, unit_price)
+SELECT o.id, o.product_id, p.title, 1, p.price FROM orders o JOIN products p ON p.id = o.product_id;
+UPDATE orders_new SET total = (SELECT COALESCE(SUM(i.quantity * i.unit_price), 0) FROM order_items i WHERE i.order_id = orders_new.id);
+
+DROP TABLE orders;
+ALTER TABLE orders_new RENAME TO orders;
+CREATE INDEX idx_orders_user_id ON orders (user_id);
@@ -0,0 +1,2 @@
+-- Existing products start out of stock until staff set their stock.
+ALTER TABLE products ADD COLUMN stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0);
@@ -0,0 +1,14 @@
+CREATE TABLE payments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    amount INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX uq_payments_reference ON payments (reference);
+CREATE INDEX idx_payments_order_id ON payments (order_id);
@@ -0,0 +1,13 @@
+-- Amounts are truncated back to whole units and their currency is dropped.
+UPDATE payments SET amount = amount / 100;
+ALTER TABLE payments DROP COLUMN currency;
+
+UPDATE order_items SET unit_price = unit_price / 100;
+
+UPDATE orders SET total = total / 100;
+ALTER TABLE orders DROP COLUMN currency;
+
+DROP INDEX idx_products_currency_price;
+UPDATE products SET price = price / 100;
+ALTER TABLE products DROP COLUMN currency;
+CREATE INDEX idx_products_price ON products (price);
@@ -0,0 +1,15 @@
+-- Amounts move from whole baht to minor units (satang, cents) and carry their
+-- currency. Everything stored so far was priced in THB. Order items take the
+-- currency of their order. SQLite integers are already 64-bit.
+ALTER TABLE products ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB';
+UPDATE products SET price = price * 100;
+DROP INDEX idx_products_price;
+CREATE INDEX idx_products_currency_price ON products (currency, price);
+
+ALTER TABLE orders ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB';
+UPDATE orders SET total = total * 100;
+
+UPDATE order_items SET unit_price = unit_price * 100;
+
+ALTER TABLE payments ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB';
+UPDATE payments SET amount = amount * 100;
@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP COLUMN refund_due;
+DROP INDEX uq_payments_pending_order;
@@ -0,0 +1,9 @@
+-- An order has at most one payment awaiting the gateway, so two attempts to
+-- pay for it cannot both charge. Should a race have left several, the latest
+-- is kept. refund_due marks an authorised payment whose order was cancelled.
+UPDATE payments SET status = 'declined', reason = 'superseded by a later attempt'
+WHERE status = 'pending'
+  AND id < (SELECT MAX(id) FROM payments AS later WHERE later.order_id = payments.order_id AND later.status = 'pending');
+CREATE UNIQUE INDEX uq_payments_pending_order ON payments (order_id) WHERE status = 'pending';
+
+ALTER TABLE payments ADD COLUMN refund_due INTEGER NOT NULL DEFAULT 0 CHECK (refund_due IN (0, 1));
@@ -0,0 +1,21 @@
+CREATE TABLE payments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    amount INTEGER NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'THB',
+    status VARCHAR(16) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    refund_due INTEGER NOT NULL DEFAULT 0 CHECK (refund_due IN (0, 1)),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
+INSERT INTO payments_new (id, order_id, reference, amount, currency, status, reason, refund_due, created_at, updated_at)
+SELECT id, order_id, reference, amount, currency, status, reason, refund_due, created_at, updated_at FROM payments;
+DROP TABLE payments;
+ALTER TABLE payments_new RENAME TO payments;
+
+CREATE UNIQUE INDEX uq_payments_reference ON payments (reference);
+CREATE INDEX idx_payments_order_id ON payments (order_id);
+CREATE UNIQUE INDEX uq_payments_pending_order ON payments (order_id) WHERE status = 'pending';
@@ -0,0 +1,24 @@
+-- A payment is a record of money taken or refused, so it outlives any attempt
+-- to delete its order: the order is refused instead. SQLite cannot change a
+-- foreign key in place, so payments is rebuilt.
+CREATE TABLE payments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    amount INTEGER NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'THB',
+    status VARCHAR(16) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    refund_due INTEGER NOT NULL DEFAULT 0 CHECK (refund_due IN (0, 1)),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE RESTRICT
+);
+INSERT INTO payments_new (id, order_id, reference, amount, currency, status, reason, refund_due, created_at, updated_at)
+SELECT id, order_id, reference, amount, currency, status, reason, refund_due, created_at, updated_at FROM payments;
+DROP TABLE payments;
+ALTER TABLE payments_new RENAME TO payments;
+
+CREATE UNIQUE INDEX uq_payments_reference ON payments (reference);
+CREATE INDEX idx_payments_order_id ON payments (order_id);
+CREATE UNIQUE INDEX uq_payments_pending_order ON payments (order_id) WHERE status = 'pending';
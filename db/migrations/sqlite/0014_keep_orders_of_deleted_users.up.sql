@@ -0,0 +1,55 @@
+-- Deleting a user must not take their orders with it: that would drop
+-- reserved stock and the payments taken for them. The user is refused instead.
+-- SQLite cannot change a foreign key in place, so orders is rebuilt, and its
+-- children with it so that they point at the new table.
+CREATE TABLE orders_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    total INTEGER NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL DEFAULT 'THB',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE RESTRICT
+);
+CREATE TABLE order_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price INTEGER NOT NULL,
+    CONSTRAINT fk_order_items_order FOREIGN KEY (order_id) REFERENCES orders_new (id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_items_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+CREATE TABLE payments_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id INTEGER NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    amount INTEGER NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'THB',
+    status VARCHAR(16) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    refund_due INTEGER NOT NULL DEFAULT 0 CHECK (refund_due IN (0, 1)),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders_new (id) ON DELETE RESTRICT
+);
+INSERT INTO orders_new (id, user_id, status, total, currency, created_at, updated_at)
+SELECT id, user_id, status, total, currency, created_at, updated_at FROM orders;
+INSERT INTO order_items_new (id, order_id, product_id, title, quantity, unit_price)
+SELECT id, order_id, product_id, title, quantity, unit_price FROM order_items;
+INSERT INTO payments_new (id, order_id, reference, amount, currency, status, reason, refund_due, created_at, updated_at)
+SELECT id, order_id, reference, amount, currency, status, reason, refund_due, created_at, updated_at FROM payments;
+DROP TABLE payments;
+DROP TABLE order_items;
+DROP TABLE orders;
+ALTER TABLE orders_new RENAME TO orders;
+ALTER TABLE order_items_new RENAME TO order_items;
+ALTER TABLE payments_new RENAME TO payments;
+
+CREATE INDEX idx_orders_user_id ON orders (user_id);
+CREATE INDEX idx_order_items_order_id ON order_items (order_id);
+CREATE UNIQUE INDEX uq_payments_reference ON payments (reference);
+CREATE INDEX idx_payments_order_id ON payments (order_id);
+CREATE UNIQUE INDEX uq_payments_pending_order ON payments (order_id) WHERE status = 'pending';
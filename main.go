@@ -1,45 +1,267 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
+	authAdapter "github.com/wittawat/go-hex/adapter/auth"
+	cartAdapter "github.com/wittawat/go-hex/adapter/cart"
+	healthAdapter "github.com/wittawat/go-hex/adapter/health"
+	"github.com/wittawat/go-hex/adapter/httpx"
+	"github.com/wittawat/go-hex/adapter/logx"
+	"github.com/wittawat/go-hex/adapter/memtx"
+	metricsAdapter "github.com/wittawat/go-hex/adapter/metrics"
 	orderAdapter "github.com/wittawat/go-hex/adapter/order"
+	paymentAdapter "github.com/wittawat/go-hex/adapter/payment"
 	productAdapter "github.com/wittawat/go-hex/adapter/product"
+	"github.com/wittawat/go-hex/adapter/sqltx"
+	tracingAdapter "github.com/wittawat/go-hex/adapter/tracing"
 	userAdapter "github.com/wittawat/go-hex/adapter/user"
+	"github.com/wittawat/go-hex/config"
+	cartPort "github.com/wittawat/go-hex/core/port/cart"
+	orderPort "github.com/wittawat/go-hex/core/port/order"
+	paymentPort "github.com/wittawat/go-hex/core/port/payment"
+	productPort "github.com/wittawat/go-hex/core/port/product"
+	uowPort "github.com/wittawat/go-hex/core/port/uow"
+	userPort "github.com/wittawat/go-hex/core/port/user"
 	"github.com/wittawat/go-hex/core/service"
 	mysql "github.com/wittawat/go-hex/db"
+	"github.com/wittawat/go-hex/db/migrations"
 	"github.com/wittawat/go-hex/routes"
+	_ "modernc.org/sqlite"
 )
 
-const PORT = ":3030"
+type repositories struct {
+	// db is nil for in-memory storage.
+	db        *sql.DB
+	user      userPort.UserOutbound
+	product   productPort.ProductOutbound
+	inventory productPort.InventoryOutbound
+	order     orderPort.OrderRepository
+	cart      cartPort.CartRepository
+	payment   paymentPort.PaymentRepository
+	uow       uowPort.UnitOfWork
+}
 
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		fatal(slog.Default(), "fail to load config", err)
+	}
+	logger := logx.New(cfg.Log, os.Stdout)
+	// the standard log package, used by some libraries, writes through logger too
+	slog.SetDefault(logger)
+	if cfg.Log.SlogLevel() > slog.LevelDebug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// cancelled on SIGINT or SIGTERM, which starts the shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	db, err := mysql.InitializeMysqlDB("mysql")
+	var repos repositories
+	switch cfg.Storage {
+	case config.StorageMemory:
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			fatal(logger, "migrate needs a database", fmt.Errorf("storage is %s", cfg.Storage))
+		}
+		logger.Info("use in-memory storage")
+		repos = newMemoryRepositories()
+	case config.StorageSQLite:
+		db, err := mysql.InitializeSqliteDB(cfg.SQLite, logger)
+		if err != nil {
+			fatal(logger, "fail to open sqlite", err)
+		}
+		if err := mysql.WaitForDB(ctx, db, cfg.Connect, logger); err != nil {
+			fatal(logger, "fail to open sqlite", err)
+		}
+		prepareDatabase(cfg, db, logger, migrations.SQLite())
+		repos = newSqlRepositories(db, cfg.Storage)
+	default:
+		db, err := mysql.InitializeMysqlDB(cfg.MySQL, cfg.Pool, logger)
+		if err != nil {
+			fatal(logger, "fail to connect mysql", err)
+		}
+		if err := mysql.WaitForDB(ctx, db, cfg.Connect, logger); err != nil {
+			fatal(logger, "fail to connect mysql", err)
+		}
+		prepareDatabase(cfg, db, logger, migrations.MySQL())
+		repos = newSqlRepositories(db, cfg.Storage)
+	}
+
+	shutdownTracing, err := tracingAdapter.Install(ctx, cfg.Tracing)
 	if err != nil {
-		log.Fatal("fail to connect mysql: ", err)
+		fatal(logger, "fail to set up tracing", err)
+	}
+
+	metrics := metricsAdapter.NewMetrics()
+	if repos.db != nil {
+		metrics.RegisterDB(repos.db, cfg.Storage)
 	}
+	repos = instrument(repos, metrics)
 
-	app := gin.Default()
+	app := gin.New()
+	// RequestID comes first so that the access log and every span see the id.
+	// Recovery comes after AccessLog and Measure so that they see the 500 of a
+	// panicking handler; it writes nothing itself, the access log reports the panic.
+	app.Use(routes.RequestID(), routes.AccessLog(logger), routes.Trace(), routes.Measure(metrics),
+		gin.CustomRecoveryWithWriter(io.Discard, httpx.Recovered), routes.RequestTimeout(cfg.HTTP.RequestTimeout))
+	app.NoRoute(httpx.NotFound)
+	routes.RegisterMetricsRoutes(app, metrics)
+
+	checker := healthAdapter.NewChecker(cfg.Health.CheckTimeout)
+	if repos.db != nil {
+		checker.Register(cfg.Storage, healthAdapter.PingDB(repos.db))
+	}
+	routes.RegisterHealthRoutes(app, healthAdapter.NewHttpHealthHandler(checker))
 
-	userRepo := userAdapter.NewMysqlUserRepository(db)
-	userService := service.NewUserService(userRepo)
+	userService := tracingAdapter.NewUserInbound(service.NewUserService(repos.user, repos.order, userAdapter.NewBcryptPasswordHasher(cfg.Auth.BcryptCost), repos.uow, logger))
 	userHandler := userAdapter.NewHttpUserHandler(userService)
-	routes.RegisterUserRoutes(app, userHandler)
+	if cfg.Auth.AdminEmail != "" {
+		if err := userService.EnsureAdmin(ctx, "admin", cfg.Auth.AdminEmail, cfg.Auth.AdminPassword); err != nil {
+			fatal(logger, "fail to create admin", err)
+		}
+	}
+
+	tokens := authAdapter.NewJwtTokenProvider(jwtSecret(cfg.Auth, logger), cfg.Auth.JWTIssuer, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+	authService := tracingAdapter.NewAuthInbound(service.NewAuthService(userService, tokens))
+	authHandler := authAdapter.NewHttpAuthHandler(authService)
+	routes.RegisterAuthRoutes(app, authHandler)
+	auth := routes.Authenticate(authService)
+
+	routes.RegisterUserRoutes(app, userHandler, auth)
 
-	productRepo := productAdapter.NewMysqlProductRepository(db)
-	productService := service.NewProductService(productRepo)
+	productService := tracingAdapter.NewProductInbound(service.NewProductService(repos.product, repos.inventory))
 	productHandler := productAdapter.NewHttpProductHandler(productService)
-	routes.RegisterProductHandler(app, productHandler)
+	routes.RegisterProductHandler(app, productHandler, auth)
 
-	orderRepo := orderAdapter.NewMysqlOrderRepository(db)
-	orderService := service.NewOrderService(orderRepo)
+	var gateway paymentPort.PaymentGateway = paymentAdapter.NewFakeGateway(paymentAdapter.FakeOutcome(cfg.Payment.FakeOutcome), cfg.Payment.FakeLatency)
+	gateway = tracingAdapter.NewPaymentGateway(metricsAdapter.NewPaymentGateway(gateway, metrics))
+	orderService := tracingAdapter.NewOrderService(service.NewOrderService(repos.order, repos.product, repos.inventory, repos.payment, gateway, cfg.Payment.RetryAfter, repos.uow, logger))
 	orderHandler := orderAdapter.NewHttpOrderHandler(orderService)
-	routes.RegisterOrderHandler(app, orderHandler)
+	routes.RegisterOrderHandler(app, orderHandler, auth)
+	routes.RegisterPaymentRoutes(app, paymentAdapter.NewHttpWebhookHandler(orderService, webhookSecret(cfg.Payment, logger)))
+
+	cartService := tracingAdapter.NewCartService(service.NewCartService(repos.cart, repos.product, orderService, repos.uow))
+	cartHandler := cartAdapter.NewHttpCartHandler(cartService)
+	routes.RegisterCartHandler(app, cartHandler, auth)
+
+	err = serve(ctx, newServer(cfg.HTTP, app), cfg.HTTP.ShutdownTimeout, logger)
+	if repos.db != nil {
+		if err := mysql.DisconnectDB(repos.db); err != nil {
+			logger.Error("fail to close database", "error", err)
+		}
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("fail to flush traces", "error", err)
+	}
+	if err != nil {
+		fatal(logger, "fail to serve", err)
+	}
+	logger.Info("server stopped")
+}
+
+// prepareDatabase runs the migrate command and exits when asked to, otherwise
+// brings the schema up to date if MigrateOnStart is set.
+func prepareDatabase(cfg config.Config, db *sql.DB, logger *slog.Logger, source fs.FS) {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, cfg.Storage, source, logger, os.Args[2:]); err != nil {
+			fatal(logger, "fail to migrate", err)
+		}
+		mysql.DisconnectDB(db)
+		os.Exit(0)
+	}
+
+	if cfg.MigrateOnStart {
+		migrator, err := mysql.NewMigrator(db, cfg.Storage, source, logger)
+		if err != nil {
+			fatal(logger, "fail to load migrations", err)
+		}
+		if _, err := migrator.Up(); err != nil {
+			fatal(logger, "fail to migrate", err)
+		}
+	}
+}
+
+func jwtSecret(cfg config.AuthConfig, logger *slog.Logger) []byte {
+	if cfg.JWTSecret != "" {
+		return []byte(cfg.JWTSecret)
+	}
+	logger.Warn("JWT_SECRET is not set, using a random secret; tokens will not survive a restart")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		fatal(logger, "fail to generate jwt secret", err)
+	}
+	return secret
+}
+
+func webhookSecret(cfg config.PaymentConfig, logger *slog.Logger) []byte {
+	if cfg.WebhookSecret != "" {
+		return []byte(cfg.WebhookSecret)
+	}
+	logger.Warn("PAYMENT_WEBHOOK_SECRET is not set, payment callbacks will be rejected")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		fatal(logger, "fail to generate webhook secret", err)
+	}
+	return secret
+}
+
+// instrument wraps every repository so that its calls are measured and traced.
+func instrument(repos repositories, metrics *metricsAdapter.Metrics) repositories {
+	repos.user = tracingAdapter.NewUserOutbound(metricsAdapter.NewUserOutbound(repos.user, metrics))
+	repos.product = tracingAdapter.NewProductOutbound(metricsAdapter.NewProductOutbound(repos.product, metrics))
+	repos.inventory = tracingAdapter.NewInventoryOutbound(metricsAdapter.NewInventoryOutbound(repos.inventory, metrics))
+	repos.order = tracingAdapter.NewOrderRepository(metricsAdapter.NewOrderRepository(repos.order, metrics))
+	repos.cart = tracingAdapter.NewCartRepository(metricsAdapter.NewCartRepository(repos.cart, metrics))
+	repos.payment = tracingAdapter.NewPaymentRepository(metricsAdapter.NewPaymentRepository(repos.payment, metrics))
+	return repos
+}
+
+// fatal logs err and exits.
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+func newMemoryRepositories() repositories {
+	products := productAdapter.NewMemoryProductRepository()
+	return repositories{
+		user:      userAdapter.NewMemoryUserRepository(),
+		product:   products,
+		inventory: products,
+		order:     orderAdapter.NewMemoryOrderRepository(),
+		cart:      cartAdapter.NewMemoryCartRepository(),
+		payment:   paymentAdapter.NewMemoryPaymentRepository(),
+		uow:       memtx.NewUnitOfWork(),
+	}
+}
 
-	if err := app.Run(PORT); err != nil {
-		log.Fatal("fail to start server: ", err)
+// newSqlRepositories serves MySQL and SQLite alike; dialect only picks the few
+// statements whose syntax differs.
+func newSqlRepositories(db *sql.DB, dialect string) repositories {
+	products := productAdapter.NewSqlProductRepository(db)
+	return repositories{
+		db:        db,
+		user:      userAdapter.NewSqlUserRepository(db),
+		product:   products,
+		inventory: products,
+		order:     orderAdapter.NewSqlOrderRepository(db),
+		cart:      cartAdapter.NewSqlCartRepository(db, dialect),
+		payment:   paymentAdapter.NewSqlPaymentRepository(db),
+		uow:       sqltx.NewUnitOfWork(db),
 	}
 }
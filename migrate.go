@@ -0,0 +1,57 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+
+	mysql "github.com/wittawat/go-hex/db"
+)
+
+// runMigrate handles "migrate up|down|status".
+func runMigrate(db *sql.DB, dialect string, source fs.FS, logger *slog.Logger, args []string) error {
+	migrator, err := mysql.NewMigrator(db, dialect, source, logger)
+	if err != nil {
+		return err
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", len(applied))
+	case "down":
+		reverted, err := migrator.Down()
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			fmt.Println("no migration to revert")
+			return nil
+		}
+		fmt.Printf("reverted %04d_%s\n", reverted.Version, reverted.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, state)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q (want up, down or status)", command)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	adapter "github.com/wittawat/go-hex/adapter/auth"
+	"github.com/wittawat/go-hex/adapter/httpx"
+	"github.com/wittawat/go-hex/core/entities"
+	port "github.com/wittawat/go-hex/core/port/auth"
+)
+
+const IdentityKey = "identity"
+
+func RegisterAuthRoutes(app *gin.Engine, authHandler *adapter.HttpAuthHandler) {
+	authRoute := app.Group("auth")
+	authRoute.POST("/login", authHandler.Login)
+	authRoute.POST("/refresh", authHandler.Refresh)
+}
+
+// Authenticate requires a valid "Authorization: Bearer <access token>" header and
+// stores the caller's identity in both the gin context and the request context.
+func Authenticate(ib port.AuthInbound) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			httpx.AbortWithError(c, entities.Unauthorized("missing bearer token"))
+			return
+		}
+
+		identity, err := ib.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			httpx.AbortWithError(c, err)
+			return
+		}
+
+		c.Set(IdentityKey, identity)
+		c.Request = c.Request.WithContext(entities.ContextWithIdentity(c.Request.Context(), identity))
+		c.Next()
+	}
+}
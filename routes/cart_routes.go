@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	adapter "github.com/wittawat/go-hex/adapter/cart"
+)
+
+func RegisterCartHandler(app *gin.Engine, cartHandler *adapter.HttpCartHandler, auth gin.HandlerFunc) {
+	cartRoute := app.Group("cart", auth)
+	cartRoute.GET("/", cartHandler.GetCart)
+	cartRoute.DELETE("/", cartHandler.ClearCart)
+	cartRoute.POST("/items", cartHandler.AddItem)
+	cartRoute.PUT("/items/:product_id", cartHandler.UpdateItem)
+	cartRoute.DELETE("/items/:product_id", cartHandler.RemoveItem)
+	cartRoute.POST("/checkout", cartHandler.Checkout)
+}
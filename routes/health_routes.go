@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	adapter "github.com/wittawat/go-hex/adapter/health"
+)
+
+// RegisterHealthRoutes adds the liveness and readiness probes; they need no token.
+func RegisterHealthRoutes(app *gin.Engine, healthHandler *adapter.HttpHealthHandler) {
+	app.GET("/healthz", healthHandler.Live)
+	app.GET("/readyz", healthHandler.Ready)
+}
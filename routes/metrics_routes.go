@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	adapter "github.com/wittawat/go-hex/adapter/metrics"
+)
+
+// RegisterMetricsRoutes serves the Prometheus scrape endpoint; like the health
+// probes it needs no token.
+func RegisterMetricsRoutes(app *gin.Engine, m *adapter.Metrics) {
+	app.GET("/metrics", gin.WrapH(m.Handler()))
+}
@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wittawat/go-hex/adapter/httpx"
+	"github.com/wittawat/go-hex/adapter/logx"
+	metrics "github.com/wittawat/go-hex/adapter/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestTimeout bounds the request context, so the repositories give up on
+// queries for requests that ran out of time. Client disconnects already cancel
+// the request context.
+func RequestTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Measure records the count, status and latency of each request by route.
+// Requests that match no route share the "unmatched" route.
+func Measure(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// Trace starts a server span for each request, continuing the trace of the
+// caller's traceparent header if there is one. Handlers reach the span through
+// the request context.
+func Trace() gin.HandlerFunc {
+	tracer := otel.Tracer("github.com/wittawat/go-hex/routes")
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("url.path", c.Request.URL.Path),
+		))
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, "")
+		}
+	}
+}
+
+// RequestID tags each request with the caller's X-Request-ID, or a fresh one,
+// and echoes it in the response header and envelope. The request context
+// carries it too, so that every line logged for the request has it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(httpx.RequestIdHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestId()
+		}
+		c.Set(httpx.RequestIdKey, id)
+		c.Header(httpx.RequestIdHeader, id)
+		c.Request = c.Request.WithContext(logx.WithRequestId(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// AccessLog logs one line per request once it is answered: at error level
+// for 5xx, warn for 4xx and info otherwise, with the errors the handlers
+// attached. The query string and headers are left out, as they may carry
+// credentials.
+func AccessLog(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", strings.Join(c.Errors.Errors(), "; ")))
+		}
+		logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
@@ -5,10 +5,13 @@ import (
 	adapter "github.com/wittawat/go-hex/adapter/order"
 )
 
-func RegisterOrderHandler(app *gin.Engine, orderHandler *adapter.HttpOrderHandler) {
-	orderRoute := app.Group("orders")
+func RegisterOrderHandler(app *gin.Engine, orderHandler *adapter.HttpOrderHandler, auth gin.HandlerFunc) {
+	orderRoute := app.Group("orders", auth)
 	orderRoute.GET("/user/:user_id", orderHandler.FindOrder)
+	orderRoute.GET("/:id", orderHandler.GetOrder)
 	orderRoute.POST("/", orderHandler.CreateOrder)
-	orderRoute.PATCH("/:id", orderHandler.UpdateOrder)
+	orderRoute.PATCH("/:id/status", orderHandler.UpdateOrderStatus)
 	orderRoute.DELETE("/:id", orderHandler.DeleteOrder)
+	orderRoute.POST("/:id/payments", orderHandler.PayOrder)
+	orderRoute.GET("/:id/payments", orderHandler.GetPayments)
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	adapter "github.com/wittawat/go-hex/adapter/payment"
+)
+
+// RegisterPaymentRoutes adds the gateway callback; the gateway signs its
+// requests instead of sending a bearer token.
+func RegisterPaymentRoutes(app *gin.Engine, webhookHandler *adapter.HttpWebhookHandler) {
+	paymentRoute := app.Group("payments")
+	paymentRoute.POST("/webhook", webhookHandler.Callback)
+}
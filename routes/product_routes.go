@@ -5,11 +5,12 @@ import (
 	adapter "github.com/wittawat/go-hex/adapter/product"
 )
 
-func RegisterProductHandler(app *gin.Engine, productHandler *adapter.HttpProductHandler) {
-	productRote := app.Group("products")
+func RegisterProductHandler(app *gin.Engine, productHandler *adapter.HttpProductHandler, auth gin.HandlerFunc) {
+	productRote := app.Group("products", auth)
 	productRote.GET("/", productHandler.GetAllProduct)
 	productRote.GET("/:id", productHandler.GetProduct)
 	productRote.POST("/", productHandler.CreateProduct)
 	productRote.PATCH("/:id", productHandler.UpdateProduct)
+	productRote.PUT("/:id/stock", productHandler.SetProductStock)
 	productRote.DELETE("/:id", productHandler.DeleteProduct)
 }
@@ -5,11 +5,11 @@ import (
 	adapter "github.com/wittawat/go-hex/adapter/user"
 )
 
-func RegisterUserRoutes(app *gin.Engine, userHandler *adapter.HttpUserHandler) {
+func RegisterUserRoutes(app *gin.Engine, userHandler *adapter.HttpUserHandler, auth gin.HandlerFunc) {
 	userRoute := app.Group("users")
 	userRoute.POST("/", userHandler.Register)
-	userRoute.GET("/", userHandler.GetAllUser)
-	userRoute.GET("/:id", userHandler.GetUser)
-	userRoute.PATCH("/:id", userHandler.UpdateUser)
-	userRoute.DELETE("/:id", userHandler.DeleteUser)
+	userRoute.GET("/", auth, userHandler.GetAllUser)
+	userRoute.GET("/:id", auth, userHandler.GetUser)
+	userRoute.PATCH("/:id", auth, userHandler.UpdateUser)
+	userRoute.DELETE("/:id", auth, userHandler.DeleteUser)
 }
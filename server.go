@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/wittawat/go-hex/config"
+)
+
+func newServer(cfg config.HTTPConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// serve runs server until ctx is cancelled, then stops accepting connections
+// and waits up to timeout for in-flight requests to finish. Requests still
+// running after that have their connections closed.
+func serve(ctx context.Context, server *http.Server, timeout time.Duration, logger *slog.Logger) error {
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("listen", "addr", server.Addr)
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shut down, wait for in-flight requests", "timeout", timeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		return fmt.Errorf("drain in-flight requests: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
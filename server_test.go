@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// slowServer is an http.Server on a free local port whose one handler
+// signals started, then holds the request until release is closed.
+type slowServer struct {
+	server   *http.Server
+	started  chan struct{}
+	release  chan struct{}
+	finished chan struct{}
+}
+
+func newSlowServer(t *testing.T) *slowServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	s := &slowServer{started: make(chan struct{}), release: make(chan struct{}), finished: make(chan struct{})}
+	s.server = &http.Server{Addr: addr, Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(s.started)
+		<-s.release
+		io.WriteString(w, "done")
+		close(s.finished)
+	})}
+	return s
+}
+
+// serve starts serve in the background; its result arrives on the channel.
+func (s *slowServer) serve(ctx context.Context, timeout time.Duration) <-chan error {
+	errs := make(chan error, 1)
+	go func() { errs <- serve(ctx, s.server, timeout, slog.New(slog.DiscardHandler)) }()
+	return errs
+}
+
+// get sends a request once the server listens and returns its body, or the error.
+func (s *slowServer) get(t *testing.T) <-chan string {
+	t.Helper()
+	bodies := make(chan string, 1)
+	go func() {
+		for {
+			resp, err := http.Get("http://" + s.server.Addr)
+			var dial *net.OpError
+			if errors.As(err, &dial) && dial.Op == "dial" {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+			if err != nil {
+				bodies <- err.Error()
+				return
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			bodies <- string(body)
+			return
+		}
+	}()
+	return bodies
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	s := newSlowServer(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	errs := s.serve(ctx, 5*time.Second)
+	bodies := s.get(t)
+	<-s.started
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case err := <-errs:
+		t.Fatalf("serve returned %v with a request in flight", err)
+	default:
+	}
+	close(s.release)
+
+	if err := <-errs; err != nil {
+		t.Fatalf("serve = %v, want nil once the request finished", err)
+	}
+	<-s.finished
+	if body := <-bodies; body != "done" {
+		t.Errorf("in-flight request got %q, want done", body)
+	}
+}
+
+func TestServeGivesUpDrainingAfterTimeout(t *testing.T) {
+	s := newSlowServer(t)
+	defer close(s.release)
+	ctx, cancel := context.WithCancel(t.Context())
+	errs := s.serve(ctx, 50*time.Millisecond)
+	s.get(t)
+	<-s.started
+
+	cancel()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("serve = %v, want the drain to fail with context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not give up draining after its timeout")
+	}
+}